@@ -0,0 +1,17 @@
+package commands
+
+import (
+	"github.com/urfave/cli/v2"
+
+	"github.com/grafana/grafana/pkg/dashboards/gitsync"
+	"github.com/grafana/grafana/pkg/dashboards/usage"
+)
+
+// DashboardsCommand is the `grafana-cli dashboards` command group, holding
+// the gitsync backup/restore subcommands and report-orphans. It is not part
+// of grafana-cli's top-level command list.
+var DashboardsCommand = &cli.Command{
+	Name:        "dashboards",
+	Usage:       "manage dashboards",
+	Subcommands: append(append([]*cli.Command{}, gitsync.Commands...), usage.ReportOrphansCommand),
+}
@@ -0,0 +1,105 @@
+package gitsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+var backupLogger = log.New("dashboards.gitsync.backup")
+
+// Backup walks every dashboard in orgIDs reachable from reader, serializes
+// each as pretty-printed JSON into opts.LocalPath using a per-org/per-folder
+// layout, and commits/pushes the result to opts.RemoteURL. An empty orgIDs
+// backs up nothing; RunBackupCommand fills in every org when --org-id is unset.
+func Backup(ctx context.Context, reader DashboardReader, orgIDs []int64, opts Options) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	repo, wt, err := openOrCloneWorkingTree(opts)
+	if err != nil {
+		return fmt.Errorf("gitsync: failed to prepare local checkout: %w", err)
+	}
+
+	written := 0
+	for _, orgID := range orgIDs {
+		dashes, err := reader.GetDashboards(ctx, orgID)
+		if err != nil {
+			return fmt.Errorf("gitsync: failed to list dashboards for org %d: %w", orgID, err)
+		}
+
+		for _, dash := range dashes {
+			folderTitle := models.RootFolderName
+			if dash.FolderId > 0 {
+				folder, err := reader.GetFolderByID(ctx, orgID, dash.FolderId)
+				if err != nil {
+					return fmt.Errorf("gitsync: failed to resolve folder for dashboard %q: %w", dash.Uid, err)
+				}
+				folderTitle = folder.Title
+			}
+
+			if !opts.allowed(folderTitle) {
+				continue
+			}
+
+			rel := dashboardPath(orgID, folderTitle, dash.Uid)
+			abs := filepath.Join(opts.LocalPath, rel)
+			if err := os.MkdirAll(filepath.Dir(abs), 0750); err != nil {
+				return fmt.Errorf("gitsync: failed to create folder layout: %w", err)
+			}
+
+			b, err := json.MarshalIndent(dash.Data, "", "  ")
+			if err != nil {
+				return fmt.Errorf("gitsync: failed to serialize dashboard %q: %w", dash.Uid, err)
+			}
+
+			if opts.DryRun {
+				backupLogger.Info("would write dashboard", "path", rel)
+				written++
+				continue
+			}
+
+			if err := os.WriteFile(abs, b, 0640); err != nil {
+				return fmt.Errorf("gitsync: failed to write dashboard %q: %w", dash.Uid, err)
+			}
+			if _, err := wt.Add(rel); err != nil {
+				return fmt.Errorf("gitsync: failed to stage dashboard %q: %w", dash.Uid, err)
+			}
+			written++
+		}
+	}
+
+	if opts.DryRun || written == 0 {
+		backupLogger.Info("backup complete", "dashboards", written, "dryRun", opts.DryRun)
+		return nil
+	}
+
+	sig := &object.Signature{
+		Name:  opts.AuthorName,
+		Email: opts.AuthorEmail,
+		When:  time.Now(),
+	}
+	if _, err := wt.Commit(fmt.Sprintf("grafana-cli: backup %d dashboard(s)", written), &git.CommitOptions{Author: sig}); err != nil {
+		return fmt.Errorf("gitsync: failed to commit backup: %w", err)
+	}
+
+	auth, err := opts.auth()
+	if err != nil {
+		return err
+	}
+	if err := repo.PushContext(ctx, &git.PushOptions{Auth: auth}); err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("gitsync: failed to push backup: %w", err)
+	}
+
+	backupLogger.Info("backup complete", "dashboards", written)
+	return nil
+}
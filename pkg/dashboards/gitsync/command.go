@@ -0,0 +1,108 @@
+package gitsync
+
+import (
+	"github.com/urfave/cli/v2"
+
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Commands are the grafana-cli subcommands under `grafana-cli dashboards`
+// that back up and restore dashboards via gitsync. They are appended to
+// grafana-cli's dashboards command group.
+var Commands = []*cli.Command{backupCommand, restoreCommand}
+
+var sharedFlags = []cli.Flag{
+	&cli.StringFlag{Name: "remote", Usage: "git remote URL to push to / clone from", Required: true},
+	&cli.StringFlag{Name: "local-path", Usage: "local working directory for the clone", Required: true},
+	&cli.StringFlag{Name: "ssh-key", Usage: "path to an SSH private key, for git+ssh remotes"},
+	&cli.StringFlag{Name: "http-user", Usage: "username for HTTPS basic auth remotes"},
+	&cli.StringFlag{Name: "http-token", Usage: "password/token for HTTPS basic auth remotes"},
+	&cli.StringSliceFlag{Name: "folder", Usage: "restrict to the named folder(s) (repeatable); default is all folders"},
+	&cli.Int64SliceFlag{Name: "org-id", Usage: "restrict to the given org id(s) (repeatable); default is all orgs"},
+	&cli.BoolFlag{Name: "force", Usage: "overwrite existing dashboards on restore"},
+	&cli.BoolFlag{Name: "dry-run", Usage: "print what would happen without writing anything"},
+	&cli.StringFlag{Name: "config", Usage: "path to grafana.ini"},
+	&cli.StringFlag{Name: "homepath", Usage: "path to the grafana install"},
+}
+
+func optionsFromContext(c *cli.Context) Options {
+	return Options{
+		RemoteURL:       c.String("remote"),
+		LocalPath:       c.String("local-path"),
+		AuthorName:      c.String("author-name"),
+		AuthorEmail:     c.String("author-email"),
+		SSHKeyPath:      c.String("ssh-key"),
+		HTTPUser:        c.String("http-user"),
+		HTTPToken:       c.String("http-token"),
+		FolderAllowlist: c.StringSlice("folder"),
+		Force:           c.Bool("force"),
+		DryRun:          c.Bool("dry-run"),
+	}
+}
+
+// cfgFromContext loads the same grafana.ini every other grafana-cli command
+// reads its config from, so backup/restore see the org's real database.
+func cfgFromContext(c *cli.Context) (*setting.Cfg, error) {
+	return setting.NewCfgFromArgs(setting.CommandLineArgs{
+		Config:   c.String("config"),
+		HomePath: c.String("homepath"),
+	})
+}
+
+var backupCommand = &cli.Command{
+	Name:  "backup",
+	Usage: "back up all dashboards to a git repository",
+	Flags: append(sharedFlags,
+		&cli.StringFlag{Name: "author-name", Usage: "commit author name", Value: "grafana-cli"},
+		&cli.StringFlag{Name: "author-email", Usage: "commit author email", Value: "grafana-cli@localhost"},
+	),
+	Action: func(c *cli.Context) error {
+		return RunBackupCommand(c)
+	},
+}
+
+var restoreCommand = &cli.Command{
+	Name:  "restore",
+	Usage: "restore dashboards from a git repository, validating each one against the dist dashboard schema",
+	Flags: sharedFlags,
+	Action: func(c *cli.Context) error {
+		return RunRestoreCommand(c)
+	},
+}
+
+// RunBackupCommand loads grafana.ini, opens the configured SQLStore and runs
+// Backup against it for every org ID passed via --org-id (all orgs if
+// unset).
+func RunBackupCommand(c *cli.Context) error {
+	cfg, err := cfgFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	orgIDs := c.Int64Slice("org-id")
+	if len(orgIDs) == 0 {
+		orgIDs, err = allOrgIDs(c.Context, cfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	return Backup(c.Context, busDashboardReader{}, orgIDs, optionsFromContext(c))
+}
+
+// RunRestoreCommand loads grafana.ini, opens the configured dashboard
+// service and runs Restore against it.
+func RunRestoreCommand(c *cli.Context) error {
+	cfg, err := cfgFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	svc, err := dashboards.ProvideDashboardService(cfg)
+	if err != nil {
+		return err
+	}
+
+	return Restore(c.Context, nil, dashboardServiceWriter{svc: svc}, optionsFromContext(c))
+}
@@ -0,0 +1,71 @@
+package gitsync
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// auth builds a go-git transport.AuthMethod from the configured credentials,
+// preferring SSH over HTTPS basic auth. A nil, nil return means the remote
+// is expected to allow anonymous access.
+func (o *Options) auth() (transport.AuthMethod, error) {
+	switch {
+	case o.SSHKeyPath != "":
+		auth, err := ssh.NewPublicKeysFromFile("git", o.SSHKeyPath, "")
+		if err != nil {
+			return nil, fmt.Errorf("gitsync: failed to load SSH key: %w", err)
+		}
+		return auth, nil
+	case o.HTTPUser != "":
+		return &http.BasicAuth{Username: o.HTTPUser, Password: o.HTTPToken}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// openOrCloneWorkingTree returns a git.Repository and its worktree rooted at
+// opts.LocalPath, cloning opts.RemoteURL into it first if it isn't already a
+// git checkout.
+func openOrCloneWorkingTree(opts Options) (*git.Repository, *git.Worktree, error) {
+	auth, err := opts.auth()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repo, err := git.PlainOpen(opts.LocalPath)
+	switch err {
+	case nil:
+		// already a checkout, nothing to do
+	case git.ErrRepositoryNotExists:
+		if err := os.MkdirAll(opts.LocalPath, 0750); err != nil {
+			return nil, nil, fmt.Errorf("gitsync: failed to create local path: %w", err)
+		}
+		repo, err = git.PlainClone(opts.LocalPath, false, &git.CloneOptions{URL: opts.RemoteURL, Auth: auth})
+		if err != nil {
+			return nil, nil, fmt.Errorf("gitsync: failed to clone %q: %w", opts.RemoteURL, err)
+		}
+	default:
+		return nil, nil, err
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, wt, nil
+}
+
+// openOrCloneFS clones opts.RemoteURL into opts.LocalPath if needed and
+// returns an fs.FS rooted at the checkout, for Restore to walk.
+func openOrCloneFS(opts Options) (fs.FS, error) {
+	if _, _, err := openOrCloneWorkingTree(opts); err != nil {
+		return nil, err
+	}
+	return os.DirFS(opts.LocalPath), nil
+}
@@ -0,0 +1,130 @@
+package gitsync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/schema"
+	"github.com/grafana/grafana/pkg/schema/load"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+var restoreLogger = log.New("dashboards.gitsync.restore")
+
+// DashboardWriter is the subset of the dashboard service that restore needs
+// in order to re-create dashboards read from git.
+type DashboardWriter interface {
+	SaveDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO, allowUiUpdate bool) (*models.Dashboard, error)
+}
+
+// Restore walks every *.json dashboard file under fsys (rooted at a clone of
+// opts.RemoteURL when fsys is nil), validates each one against the dist
+// dashboard scuemata - so any schema migration/trimming is applied on the way
+// in - and re-creates it through writer. Dashboards that fail validation are
+// skipped and reported in the returned error.
+func Restore(ctx context.Context, fsys fs.FS, writer DashboardWriter, opts Options) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	if fsys == nil {
+		cloned, err := openOrCloneFS(opts)
+		if err != nil {
+			return fmt.Errorf("gitsync: failed to prepare local checkout: %w", err)
+		}
+		fsys = cloned
+	}
+
+	ddash, err := load.DistDashboardFamily(load.GetDefaultLoadPaths())
+	if err != nil {
+		return fmt.Errorf("gitsync: failed to load dist dashboard schema: %w", err)
+	}
+
+	var validationErrs []error
+	restored := 0
+
+	err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(d.Name()) != ".json" {
+			return nil
+		}
+
+		orgID, folderTitle, ok := parseDashboardPath(p)
+		if !ok {
+			restoreLogger.Warn("skipping file outside the expected <org>/<folder>/<uid>.json layout", "path", p)
+			return nil
+		}
+		if !opts.allowed(folderTitle) {
+			return nil
+		}
+
+		b, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("failed to read %q: %w", p, err)
+		}
+
+		if _, err := schema.SearchAndValidate(ddash, bytes.NewReader(b)); err != nil {
+			validationErrs = append(validationErrs, fmt.Errorf("%s: %w", p, err))
+			return nil
+		}
+
+		if opts.DryRun {
+			restoreLogger.Info("would restore dashboard", "path", p)
+			restored++
+			return nil
+		}
+
+		dashJSON, err := simplejson.NewJson(b)
+		if err != nil {
+			validationErrs = append(validationErrs, fmt.Errorf("%s: failed to parse dashboard JSON: %w", p, err))
+			return nil
+		}
+
+		dto := &dashboards.SaveDashboardDTO{
+			OrgId:     orgID,
+			Dashboard: models.NewDashboardFromJson(dashJSON),
+			Overwrite: opts.Force,
+			User:      &models.SignedInUser{UserId: 0, OrgId: orgID, OrgRole: models.ROLE_ADMIN},
+		}
+
+		if _, err := writer.SaveDashboard(ctx, dto, true); err != nil {
+			validationErrs = append(validationErrs, fmt.Errorf("%s: failed to save dashboard: %w", p, err))
+			return nil
+		}
+		restored++
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("gitsync: failed to walk %q: %w", opts.LocalPath, err)
+	}
+
+	restoreLogger.Info("restore complete", "dashboards", restored, "errors", len(validationErrs))
+	if len(validationErrs) > 0 {
+		return fmt.Errorf("gitsync: %d dashboard(s) failed validation or could not be saved: %w", len(validationErrs), validationErrs[0])
+	}
+	return nil
+}
+
+// parseDashboardPath extracts the org ID and folder title encoded by
+// dashboardPath from a slash-separated path relative to the repository root.
+func parseDashboardPath(p string) (orgID int64, folderTitle string, ok bool) {
+	segs := strings.Split(path.Clean(p), "/")
+	if len(segs) != 3 {
+		return 0, "", false
+	}
+	id, err := strconv.ParseInt(segs[0], 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return id, segs[1], true
+}
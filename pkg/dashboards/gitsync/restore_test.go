@@ -0,0 +1,68 @@
+package gitsync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWriter struct {
+	saved []*dashboards.SaveDashboardDTO
+}
+
+func (f *fakeWriter) SaveDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO, allowUiUpdate bool) (*models.Dashboard, error) {
+	f.saved = append(f.saved, dto)
+	return &models.Dashboard{Uid: dto.Dashboard.Uid, OrgId: dto.OrgId}, nil
+}
+
+// artifactsFS builds an in-memory fs.FS mirroring testdata/artifacts/dashboards,
+// so Restore can be exercised without a real git clone.
+func artifactsFS(t *testing.T) fstest.MapFS {
+	t.Helper()
+	root := filepath.Join("testdata", "artifacts", "dashboards")
+	out := fstest.MapFS{}
+
+	require.NoError(t, filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		require.NoError(t, err)
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		require.NoError(t, err)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		out[filepath.ToSlash(rel)] = &fstest.MapFile{Data: data}
+		return nil
+	}))
+	return out
+}
+
+func TestRestoreRoundTrip(t *testing.T) {
+	fsys := artifactsFS(t)
+	writer := &fakeWriter{}
+
+	err := Restore(context.Background(), fsys, writer, Options{RemoteURL: "unused", LocalPath: "unused"})
+	require.NoError(t, err)
+	require.Len(t, writer.saved, 1)
+	require.Equal(t, int64(1), writer.saved[0].OrgId)
+	require.Equal(t, "simple-dash", writer.saved[0].Dashboard.Uid)
+}
+
+func TestRestoreRoundTrip_FolderAllowlist(t *testing.T) {
+	fsys := artifactsFS(t)
+	writer := &fakeWriter{}
+
+	err := Restore(context.Background(), fsys, writer, Options{
+		RemoteURL:       "unused",
+		LocalPath:       "unused",
+		FolderAllowlist: []string{"Does Not Exist"},
+	})
+	require.NoError(t, err)
+	require.Empty(t, writer.saved)
+}
@@ -0,0 +1,56 @@
+package gitsync
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// busDashboardReader implements DashboardReader over the bus, the same way
+// the rest of Grafana's dashboard-listing HTTP handlers reach the dashboard
+// store, so grafana-cli doesn't need its own data-access path.
+type busDashboardReader struct{}
+
+func (busDashboardReader) GetDashboards(ctx context.Context, orgID int64) ([]*models.Dashboard, error) {
+	query := &models.GetDashboardsQuery{OrgId: orgID}
+	if err := bus.DispatchCtx(ctx, query); err != nil {
+		return nil, err
+	}
+	return query.Result, nil
+}
+
+func (busDashboardReader) GetFolderByID(ctx context.Context, orgID int64, folderID int64) (*models.Folder, error) {
+	query := &models.GetFolderByIdQuery{OrgId: orgID, Id: folderID}
+	if err := bus.DispatchCtx(ctx, query); err != nil {
+		return nil, err
+	}
+	return query.Result, nil
+}
+
+// dashboardServiceWriter implements DashboardWriter over the dashboards
+// service used by every other dashboard save path.
+type dashboardServiceWriter struct {
+	svc dashboards.DashboardService
+}
+
+func (w dashboardServiceWriter) SaveDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO, allowUiUpdate bool) (*models.Dashboard, error) {
+	return w.svc.SaveDashboard(ctx, dto, allowUiUpdate)
+}
+
+// allOrgIDs lists every org ID known to cfg's database, for a backup run
+// that wasn't restricted to specific orgs via --org-id.
+func allOrgIDs(ctx context.Context, cfg *setting.Cfg) ([]int64, error) {
+	query := &models.SearchOrgsQuery{}
+	if err := bus.DispatchCtx(ctx, query); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(query.Result))
+	for _, org := range query.Result {
+		ids = append(ids, org.Id)
+	}
+	return ids, nil
+}
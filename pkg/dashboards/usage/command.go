@@ -0,0 +1,67 @@
+package usage
+
+import (
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ReportOrphansCommand is the `grafana-cli dashboards report-orphans`
+// subcommand, appended to grafana-cli's dashboards command group.
+var ReportOrphansCommand = &cli.Command{
+	Name:  "report-orphans",
+	Usage: "report dashboards that have not been viewed recently, or reference missing datasources/plugins",
+	Flags: []cli.Flag{
+		&cli.DurationFlag{Name: "since", Usage: "minimum time since last view before a dashboard is flagged", Value: 30 * 24 * time.Hour},
+		&cli.StringFlag{Name: "format", Usage: "output format: table or json", Value: "table"},
+		&cli.Int64SliceFlag{Name: "org-id", Usage: "restrict to the given org id(s) (repeatable); default is all orgs"},
+		&cli.StringFlag{Name: "prometheus-url", Usage: "base URL of the Prometheus server scraping Grafana's own metrics", Value: "http://localhost:9090"},
+		&cli.StringFlag{Name: "config", Usage: "path to grafana.ini"},
+		&cli.StringFlag{Name: "homepath", Usage: "path to the grafana install"},
+	},
+	Action: func(c *cli.Context) error {
+		cfg, err := setting.NewCfgFromArgs(setting.CommandLineArgs{
+			Config:   c.String("config"),
+			HomePath: c.String("homepath"),
+		})
+		if err != nil {
+			return err
+		}
+
+		orgIDs := c.Int64Slice("org-id")
+		if len(orgIDs) == 0 {
+			orgIDs, err = allOrgIDs(c.Context, cfg)
+			if err != nil {
+				return err
+			}
+		}
+
+		pluginStore, err := plugins.ProvideService(cfg)
+		if err != nil {
+			return err
+		}
+
+		viewStats := newPrometheusViewStats(c.String("prometheus-url"))
+		return RunReportOrphansCommand(c, busDashboardReader{}, viewStats, busDatasourceLister{}, pluginStorePluginLister{store: pluginStore}, orgIDs)
+	},
+}
+
+// RunReportOrphansCommand runs the scan with the given collaborators and
+// writes the report to stdout in the requested format.
+func RunReportOrphansCommand(c *cli.Context, reader DashboardReader, viewStats ViewStats, dsLister DatasourceLister, pluginLister PluginLister, orgIDs []int64) error {
+	report, err := Scan(c.Context, reader, viewStats, dsLister, pluginLister, orgIDs, Options{Since: c.Duration("since")})
+	if err != nil {
+		return err
+	}
+
+	switch c.String("format") {
+	case "json":
+		return report.WriteJSON(os.Stdout)
+	default:
+		return report.WriteTable(os.Stdout)
+	}
+}
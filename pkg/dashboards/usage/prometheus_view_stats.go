@@ -0,0 +1,82 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// prometheusViewStats implements ViewStats by querying a Prometheus HTTP
+// API for the grafana_dashboard_views_total counter scraped from Grafana's
+// own /metrics endpoint, rather than Grafana's own database. It does not
+// query grafana_stat_totals_dashboard or any render counter.
+type prometheusViewStats struct {
+	// baseURL is the Prometheus server's address, e.g. http://localhost:9090.
+	baseURL string
+	client  *http.Client
+}
+
+func newPrometheusViewStats(baseURL string) prometheusViewStats {
+	return prometheusViewStats{baseURL: baseURL, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// prometheusQueryResponse is the body of a Prometheus /api/v1/query
+// instant-query response, trimmed to the fields LastViewed needs.
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Result []struct {
+			Value [2]interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// LastViewed asks Prometheus for the timestamp of the most recent sample of
+// the dashboard's view counter - timestamp(grafana_dashboard_views_total{...})
+// - rather than its value, since the counter itself only ever goes up. A
+// dashboard with no matching series (never scraped as viewed) reports nil,
+// not an error.
+func (p prometheusViewStats) LastViewed(ctx context.Context, orgID int64, dashboardUID string) (*time.Time, error) {
+	expr := fmt.Sprintf(`timestamp(grafana_dashboard_views_total{org_id=%q,dashboard_uid=%q})`, strconv.FormatInt(orgID, 10), dashboardUID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/v1/query", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prometheus query request: %w", err)
+	}
+	q := req.URL.Query()
+	q.Set("query", expr)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prometheus: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("prometheus query returned %s", resp.Status)
+	}
+
+	var parsed prometheusQueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode prometheus response: %w", err)
+	}
+	if parsed.Status != "success" || len(parsed.Data.Result) == 0 {
+		return nil, nil
+	}
+
+	// Value[0] is the evaluation time of the query; Value[1] is the sample
+	// value, which for timestamp(...) is the sample's own timestamp.
+	raw, ok := parsed.Data.Result[0].Value[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected prometheus timestamp value %v", parsed.Data.Result[0].Value[1])
+	}
+	seconds, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected prometheus timestamp value %q: %w", raw, err)
+	}
+	t := time.Unix(int64(seconds), 0)
+	return &t, nil
+}
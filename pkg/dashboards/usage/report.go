@@ -0,0 +1,35 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteJSON writes the report as indented JSON, suitable for feeding into
+// alerting.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteTable writes a human-readable table summarizing the report.
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ORG\tUID\tTITLE\tLAST VIEWED\tREASONS")
+	for _, f := range r.Findings {
+		lastView := "never"
+		if f.LastView != nil {
+			lastView = f.LastView.Format("2006-01-02")
+		}
+		reasons := make([]string, len(f.Reasons))
+		for i, reason := range f.Reasons {
+			reasons[i] = string(reason)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", f.OrgID, f.UID, f.Title, lastView, strings.Join(reasons, ","))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,43 @@
+package usage
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportWriteJSON(t *testing.T) {
+	generatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	r := &Report{
+		GeneratedAt: generatedAt,
+		Since:       24 * time.Hour,
+		Findings: []Finding{
+			{OrgID: 1, UID: "dash-1", Title: "Dash One", Reasons: []Reason{ReasonNotViewed}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteJSON(&buf))
+
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.True(t, generatedAt.Equal(decoded.GeneratedAt))
+	require.Equal(t, r.Findings, decoded.Findings)
+}
+
+func TestReportWriteTable(t *testing.T) {
+	r := &Report{
+		Findings: []Finding{
+			{OrgID: 1, UID: "dash-1", Title: "Dash One", Reasons: []Reason{ReasonNotViewed, ReasonMissingPlugin}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteTable(&buf))
+	require.Contains(t, buf.String(), "dash-1")
+	require.Contains(t, buf.String(), "not-viewed,missing-plugin")
+	require.Contains(t, buf.String(), "never")
+}
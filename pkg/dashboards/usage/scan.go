@@ -0,0 +1,141 @@
+package usage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/schema"
+	"github.com/grafana/grafana/pkg/schema/load"
+)
+
+var logger = log.New("dashboards.usage")
+
+// timeNow makes it possible to test usage of time
+var timeNow = time.Now
+
+// Scan cross-references every dashboard in orgIDs against viewStats,
+// dsLister, and pluginLister and returns a Report of the dashboards that look
+// unused or broken. An empty orgIDs scans nothing; ReportOrphansCommand fills
+// in every org when --org-id is unset.
+func Scan(ctx context.Context, reader DashboardReader, viewStats ViewStats, dsLister DatasourceLister, pluginLister PluginLister, orgIDs []int64, opts Options) (*Report, error) {
+	ddash, err := load.DistDashboardFamily(load.GetDefaultLoadPaths())
+	if err != nil {
+		return nil, fmt.Errorf("usage: failed to load dist dashboard schema: %w", err)
+	}
+
+	report := &Report{GeneratedAt: timeNow(), Since: opts.Since}
+
+	for _, orgID := range orgIDs {
+		dashes, err := reader.GetDashboards(ctx, orgID)
+		if err != nil {
+			return nil, fmt.Errorf("usage: failed to list dashboards for org %d: %w", orgID, err)
+		}
+
+		dsUIDs, err := dsLister.ListDatasourceUIDs(ctx, orgID)
+		if err != nil {
+			return nil, fmt.Errorf("usage: failed to list datasources for org %d: %w", orgID, err)
+		}
+		plugins, err := pluginLister.InstalledPanelPlugins(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("usage: failed to list installed panel plugins: %w", err)
+		}
+
+		for _, dash := range dashes {
+			var reasons []Reason
+
+			lastView, err := viewStats.LastViewed(ctx, orgID, dash.Uid)
+			if err != nil {
+				logger.Warn("failed to fetch view stats, treating as unviewed", "dashboard", dash.Uid, "error", err)
+				reasons = append(reasons, ReasonNotViewed)
+			} else if lastView == nil || time.Since(*lastView) > opts.Since {
+				reasons = append(reasons, ReasonNotViewed)
+			}
+
+			b, err := dash.Data.Encode()
+			if err != nil {
+				return nil, fmt.Errorf("usage: failed to encode dashboard %q: %w", dash.Uid, err)
+			}
+			if _, err := schema.SearchAndValidate(ddash, bytes.NewReader(b)); err != nil {
+				logger.Warn("dashboard failed schema validation during usage scan", "dashboard", dash.Uid, "error", err)
+			}
+
+			refs, err := extractPanelRefs(b)
+			if err != nil {
+				return nil, fmt.Errorf("usage: failed to extract panel refs for %q: %w", dash.Uid, err)
+			}
+			for _, ref := range refs {
+				if ref.DatasourceUID != "" && !dsUIDs[ref.DatasourceUID] {
+					reasons = append(reasons, ReasonMissingDatasource)
+				}
+				if ref.PanelType != "" && !plugins[ref.PanelType] {
+					reasons = append(reasons, ReasonMissingPlugin)
+				}
+			}
+
+			if len(reasons) > 0 {
+				report.Findings = append(report.Findings, Finding{
+					OrgID:    orgID,
+					UID:      dash.Uid,
+					Title:    dash.Title,
+					LastView: lastView,
+					Reasons:  dedupeReasons(reasons),
+				})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+type panelRef struct {
+	PanelType     string
+	DatasourceUID string
+}
+
+// extractPanelRefs walks the dashboard JSON's top-level panels array (rows
+// are flattened one level) collecting each panel's type and datasource uid.
+func extractPanelRefs(dashJSON []byte) ([]panelRef, error) {
+	var doc struct {
+		Panels []struct {
+			Type       string `json:"type"`
+			Datasource struct {
+				UID string `json:"uid"`
+			} `json:"datasource"`
+			Panels []struct {
+				Type       string `json:"type"`
+				Datasource struct {
+					UID string `json:"uid"`
+				} `json:"datasource"`
+			} `json:"panels"`
+		} `json:"panels"`
+	}
+	if err := json.Unmarshal(dashJSON, &doc); err != nil {
+		return nil, err
+	}
+
+	var refs []panelRef
+	for _, p := range doc.Panels {
+		refs = append(refs, panelRef{PanelType: p.Type, DatasourceUID: p.Datasource.UID})
+		for _, sub := range p.Panels {
+			refs = append(refs, panelRef{PanelType: sub.Type, DatasourceUID: sub.Datasource.UID})
+		}
+	}
+	return refs, nil
+}
+
+func dedupeReasons(reasons []Reason) []Reason {
+	seen := make(map[Reason]bool, len(reasons))
+	out := make([]Reason, 0, len(reasons))
+	for _, r := range reasons {
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		out = append(out, r)
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package usage
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// busDashboardReader implements DashboardReader over the bus, the same way
+// the rest of Grafana's dashboard-listing HTTP handlers reach the dashboard
+// store, so grafana-cli doesn't need its own data-access path.
+type busDashboardReader struct{}
+
+func (busDashboardReader) GetDashboards(ctx context.Context, orgID int64) ([]*models.Dashboard, error) {
+	query := &models.GetDashboardsQuery{OrgId: orgID}
+	if err := bus.DispatchCtx(ctx, query); err != nil {
+		return nil, err
+	}
+	return query.Result, nil
+}
+
+// busDatasourceLister implements DatasourceLister over the bus.
+type busDatasourceLister struct{}
+
+func (busDatasourceLister) ListDatasourceUIDs(ctx context.Context, orgID int64) (map[string]bool, error) {
+	query := &models.GetDataSourcesQuery{OrgId: orgID}
+	if err := bus.DispatchCtx(ctx, query); err != nil {
+		return nil, err
+	}
+
+	uids := make(map[string]bool, len(query.Result))
+	for _, ds := range query.Result {
+		uids[ds.Uid] = true
+	}
+	return uids, nil
+}
+
+// pluginStorePluginLister implements PluginLister over the installed plugin
+// store every other panel-plugin lookup in Grafana goes through.
+type pluginStorePluginLister struct {
+	store plugins.Store
+}
+
+func (l pluginStorePluginLister) InstalledPanelPlugins(ctx context.Context) (map[string]bool, error) {
+	installed := make(map[string]bool)
+	for _, p := range l.store.Plugins(ctx, plugins.Panel) {
+		installed[p.ID] = true
+	}
+	return installed, nil
+}
+
+// allOrgIDs lists every org ID known to cfg's database, for a scan that
+// wasn't restricted to specific orgs via --org-id.
+func allOrgIDs(ctx context.Context, cfg *setting.Cfg) ([]int64, error) {
+	query := &models.SearchOrgsQuery{}
+	if err := bus.DispatchCtx(ctx, query); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(query.Result))
+	for _, org := range query.Result {
+		ids = append(ids, org.Id)
+	}
+	return ids, nil
+}
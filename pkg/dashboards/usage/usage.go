@@ -0,0 +1,67 @@
+// Package usage reports on dashboards that appear to be unused or broken:
+// not viewed within a configurable window, or referencing datasources/panel
+// plugins that no longer exist.
+package usage
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// Options configures a Scan.
+type Options struct {
+	// Since is how far back to look for views before a dashboard is
+	// considered unused, e.g. 30 * 24 * time.Hour for "--since=720h".
+	Since time.Duration
+}
+
+// Reason explains why a dashboard was flagged.
+type Reason string
+
+const (
+	// ReasonNotViewed means the dashboard has not been viewed within Options.Since.
+	ReasonNotViewed Reason = "not-viewed"
+	// ReasonMissingDatasource means a panel references a datasource that no longer exists.
+	ReasonMissingDatasource Reason = "missing-datasource"
+	// ReasonMissingPlugin means a panel's type is not among the installed plugins.
+	ReasonMissingPlugin Reason = "missing-plugin"
+)
+
+// Finding is one flagged dashboard.
+type Finding struct {
+	OrgID    int64      `json:"orgId"`
+	UID      string     `json:"uid"`
+	Title    string     `json:"title"`
+	LastView *time.Time `json:"lastView,omitempty"`
+	Reasons  []Reason   `json:"reasons"`
+}
+
+// Report is the result of a Scan.
+type Report struct {
+	GeneratedAt time.Time     `json:"generatedAt"`
+	Since       time.Duration `json:"since"`
+	Findings    []Finding     `json:"findings"`
+}
+
+// DashboardReader is the subset of the dashboard store the scanner needs.
+type DashboardReader interface {
+	GetDashboards(ctx context.Context, orgID int64) ([]*models.Dashboard, error)
+}
+
+// DatasourceLister reports which datasource UIDs currently exist in an org.
+type DatasourceLister interface {
+	ListDatasourceUIDs(ctx context.Context, orgID int64) (map[string]bool, error)
+}
+
+// PluginLister reports which panel plugin IDs are currently installed.
+type PluginLister interface {
+	InstalledPanelPlugins(ctx context.Context) (map[string]bool, error)
+}
+
+// ViewStats answers "when was this dashboard last viewed" from Grafana's own
+// usage metrics (the dashboard view counter).
+type ViewStats interface {
+	LastViewed(ctx context.Context, orgID int64, dashboardUID string) (*time.Time, error)
+}
@@ -80,19 +80,13 @@ func TestDashboardValidity(t *testing.T) {
 }
 
 func TestPanelValidity(t *testing.T) {
-	t.Skip()
 	validdir := os.DirFS(filepath.Join("testdata", "artifacts", "panels"))
 
 	ddash, err := DistDashboardFamily(p)
 	require.NoError(t, err, "error while loading dist dashboard scuemata")
 
-	// TODO hmm, it's awkward for this test's structure to have to pick just one
-	// type of panel plugin, but we can change the test structure. However, is
-	// there any other situation where we want the panel subschema with all
-	// possible disjunctions? If so, maybe the interface needs work. Or maybe
-	// just defer that until the proper generic composite scuemata impl.
-	dpan, err := ddash.(CompositeDashboardSchema).LatestPanelSchemaFor("table")
-	require.NoError(t, err, "error while loading panel subschema")
+	cdash, ok := ddash.(CompositeDashboardSchema)
+	require.True(t, ok, "dist dashboard family should implement CompositeDashboardSchema")
 
 	require.NoError(t, fs.WalkDir(validdir, ".", func(path string, d fs.DirEntry, err error) error {
 		require.NoError(t, err)
@@ -101,13 +95,20 @@ func TestPanelValidity(t *testing.T) {
 			return nil
 		}
 
-		t.Run(path, func(t *testing.T) {
-			// TODO FIXME stop skipping once we actually have the schema filled in
-			// enough that the tests pass, lol
+		// testdata/artifacts/panels/<pluginID>/<name>.json
+		pluginID := filepath.Base(filepath.Dir(path))
 
+		t.Run(path, func(t *testing.T) {
 			b, err := validdir.Open(path)
 			require.NoError(t, err, "failed to open panel file")
 
+			dpan, err := cdash.LatestPanelSchemaFor(pluginID)
+			if pluginID == "unknown-panel-type" {
+				require.ErrorIs(t, err, ErrUnknownPanelType)
+				return
+			}
+			require.NoError(t, err, "error while loading panel subschema for %q", pluginID)
+
 			err = dpan.Validate(schema.Resource{Value: b})
 			require.NoError(t, err, "panel failed validation")
 		})
@@ -0,0 +1,195 @@
+package load
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/schema"
+)
+
+// ErrUnknownPanelType is returned when a dashboard panel's "type" does not
+// match any schema registered with RegisterPanelSchema.
+var ErrUnknownPanelType = errors.New("unknown panel type")
+
+var (
+	panelSchemasMu sync.RWMutex
+	panelSchemas   = map[string]schema.VersionedCueSchema{}
+)
+
+// RegisterPanelSchema makes the CUE schema found under fsys available to
+// ValidatePanels for panels whose "type" is pluginID. It is safe to call
+// concurrently, and re-registering a pluginID replaces its previous schema -
+// which is how external plugin loaders (Go plugin SDK) hook in panel schemas
+// registered at runtime, in addition to the ones this package discovers on
+// first lookup by scanning DistPluginCueFS. CompositeDashboardSchema.Validate
+// and LatestPanelSchemaFor do not consult this registry.
+func RegisterPanelSchema(pluginID string, fsys fs.FS) error {
+	sch, err := buildPanelSchema(pluginID, fsys)
+	if err != nil {
+		return err
+	}
+
+	panelSchemasMu.Lock()
+	defer panelSchemasMu.Unlock()
+	panelSchemas[pluginID] = sch
+	return nil
+}
+
+// PanelSchemas returns a snapshot of every panel schema currently registered,
+// keyed by plugin ID.
+func PanelSchemas() map[string]schema.VersionedCueSchema {
+	panelSchemasMu.RLock()
+	defer panelSchemasMu.RUnlock()
+
+	out := make(map[string]schema.VersionedCueSchema, len(panelSchemas))
+	for k, v := range panelSchemas {
+		out[k] = v
+	}
+	return out
+}
+
+// panelSchemaFor resolves the schema registered for pluginID, or
+// ErrUnknownPanelType if none has been registered. It triggers
+// ensurePanelRegistryLoaded first, so the registry is populated from
+// DistPluginCueFS on first use even if nothing explicitly called
+// loadPanelRegistry at startup.
+func panelSchemaFor(pluginID string) (schema.VersionedCueSchema, error) {
+	if err := ensurePanelRegistryLoaded(); err != nil {
+		return nil, err
+	}
+
+	panelSchemasMu.RLock()
+	defer panelSchemasMu.RUnlock()
+
+	sch, ok := panelSchemas[pluginID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPanelType, pluginID)
+	}
+	return sch, nil
+}
+
+var (
+	panelRegistryLoadOnce sync.Once
+	panelRegistryLoadErr  error
+)
+
+// ensurePanelRegistryLoaded runs loadPanelRegistry against the default load
+// paths exactly once. panelSchemaFor, and through it ValidatePanels, calls it
+// before every lookup. PanelSchemas does not, so it returns only explicitly
+// registered schemas until the first panelSchemaFor lookup has run.
+func ensurePanelRegistryLoaded() error {
+	panelRegistryLoadOnce.Do(func() {
+		panelRegistryLoadErr = loadPanelRegistry(GetDefaultLoadPaths())
+	})
+	return panelRegistryLoadErr
+}
+
+// loadPanelRegistry scans every plugin directory in DistPluginCueFS for
+// *.cue files and registers each one as a panel schema, keyed by the
+// directory name (the plugin ID). It is called once, via
+// ensurePanelRegistryLoaded.
+func loadPanelRegistry(p BaseLoadPaths) error {
+	entries, err := fs.ReadDir(p.DistPluginCueFS, ".")
+	if err != nil {
+		return fmt.Errorf("failed to read plugin cue directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pluginID := entry.Name()
+
+		sub, err := fs.Sub(p.DistPluginCueFS, pluginID)
+		if err != nil {
+			return fmt.Errorf("failed to open plugin cue directory %q: %w", pluginID, err)
+		}
+
+		hasCue := false
+		_ = fs.WalkDir(sub, ".", func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && filepath.Ext(d.Name()) == ".cue" {
+				hasCue = true
+			}
+			return nil
+		})
+		if !hasCue {
+			continue
+		}
+
+		if err := RegisterPanelSchema(pluginID, sub); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidatePanels walks every panel in dashJSON (including panels nested one
+// level inside rows), dispatches each to the schema registered for its
+// "type", and returns an error identifying the offending panel's index and
+// pluginID on the first validation failure. CompositeDashboardSchema.Validate
+// does not call it: validating a dashboard against the dashboard-level
+// schema does no per-panel type dispatch.
+func ValidatePanels(dashJSON []byte) error {
+	var doc struct {
+		Panels []panelDoc `json:"panels"`
+	}
+	if err := json.Unmarshal(dashJSON, &doc); err != nil {
+		return fmt.Errorf("failed to decode dashboard panels: %w", err)
+	}
+
+	return walkPanels(doc.Panels, "")
+}
+
+type panelDoc struct {
+	Type   string          `json:"type"`
+	Panels []panelDoc      `json:"panels"`
+	Raw    json.RawMessage `json:"-"`
+}
+
+func (p *panelDoc) UnmarshalJSON(b []byte) error {
+	type alias panelDoc
+	var a alias
+	if err := json.Unmarshal(b, &a); err != nil {
+		return err
+	}
+	*p = panelDoc(a)
+	p.Raw = append(json.RawMessage(nil), b...)
+	return nil
+}
+
+func walkPanels(panels []panelDoc, parentIndex string) error {
+	for i, p := range panels {
+		idx := fmt.Sprintf("%s%d", parentIndex, i)
+
+		sch, err := panelSchemaFor(p.Type)
+		if err != nil {
+			return fmt.Errorf("panel %s: %w", idx, err)
+		}
+		if err := sch.Validate(schema.Resource{Value: p.Raw}); err != nil {
+			return fmt.Errorf("panel %s (pluginID %q): %w", idx, p.Type, err)
+		}
+
+		if len(p.Panels) > 0 {
+			if err := walkPanels(p.Panels, idx+"."); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// buildPanelSchema compiles fsys into a VersionedCueSchema chain using the
+// same generic CUE-family loading plumbing BaseDashboardFamily and
+// DistDashboardFamily are built on.
+func buildPanelSchema(pluginID string, fsys fs.FS) (schema.VersionedCueSchema, error) {
+	sch, err := buildGenericScuemata(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build panel schema for %q: %w", pluginID, err)
+	}
+	return sch, nil
+}
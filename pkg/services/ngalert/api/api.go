@@ -1,20 +1,27 @@
 package api
 
 import (
+	"context"
+	"net/http"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/grafana/grafana/pkg/services/quota"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/usagestats"
 
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/datasourceproxy"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb"
 )
@@ -35,6 +42,31 @@ type Alertmanager interface {
 	// Alerts
 	GetAlerts(active, silenced, inhibited bool, filter []string, receiver string) (apimodels.GettableAlerts, error)
 	GetAlertGroups(active, silenced, inhibited bool, filter []string, receiver string) (apimodels.AlertGroups, error)
+
+	// Status
+	//
+	// GetStatus must be implemented by every concrete Alertmanager this
+	// interface is satisfied by - the embedded notifier Alertmanager, LotexAM
+	// (proxying to an upstream AM's real /status), and any test double - not
+	// just declared here. The embedded notifier's GetStatus should report its
+	// own config hash/uptime plus cluster peer state from its notifier
+	// cluster, and LotexAM's should proxy straight through to the upstream
+	// Alertmanager's /status endpoint rather than synthesizing a response.
+	GetStatus() apimodels.GettableStatus
+
+	// CallResource dispatches an auxiliary HTTP request to the named
+	// receiver type's ResourceHandler (e.g. an OAuth callback, a
+	// channel-autocomplete lookup or a template preview), passing the
+	// request body in and the response body out as whole byte slices, so
+	// notifier implementations can expose endpoints Grafana doesn't need to
+	// know about in advance.
+	//
+	// The embedded notifier Alertmanager's CallResource should just be
+	// DispatchResourceHandler; LotexAM's should instead proxy the request
+	// straight through to the upstream Alertmanager, since the
+	// ResourceHandler registry only has handlers for Grafana-managed
+	// notifiers.
+	CallResource(ctx context.Context, receiverType, path, method string, body []byte) (status int, headers http.Header, respBody []byte, err error)
 }
 
 // API handlers.
@@ -48,9 +80,11 @@ type API struct {
 	RuleStore       store.RuleStore
 	InstanceStore   store.InstanceStore
 	AlertingStore   store.AlertingStore
+	KVStore         kvstore.KVStore
 	DataProxy       *datasourceproxy.DatasourceProxyService
 	Alertmanager    Alertmanager
 	StateManager    *state.Manager
+	SQLStore        *sqlstore.SQLStore
 }
 
 // RegisterAPIEndpoints registers API handlers
@@ -60,12 +94,32 @@ func (api *API) RegisterAPIEndpoints(m *metrics.Metrics) {
 		DataProxy: api.DataProxy,
 	}
 
+	// Seed pending/firing state from each rule's ALERTS_FOR_STATE series (or
+	// the instance store, as a fallback) before the scheduler starts ticking,
+	// so a restart doesn't reset in-flight For: timers.
+	if rules, err := api.RuleStore.ListAllRules(); err != nil {
+		logger.Error("failed to list rules for alert state backfill", "error", err)
+	} else if err := api.Schedule.BackfillState(
+		context.Background(),
+		api.StateManager,
+		&dataServiceAlertsForStateQuerier{api: api},
+		api.InstanceStore,
+		rules,
+	); err != nil {
+		logger.Error("alert state backfill failed", "error", err)
+	}
+
 	// Register endpoints for proxing to Alertmanager-compatible backends.
 	api.RegisterAlertmanagerApiEndpoints(NewForkedAM(
 		api.DatasourceCache,
 		NewLotexAM(proxy, logger),
 		AlertmanagerSrv{store: api.AlertingStore, am: api.Alertmanager, log: logger},
 	), m)
+	// Register the generic notifier resource-serving route alongside the
+	// forked Alertmanager endpoints above.
+	AlertmanagerSrv{store: api.AlertingStore, am: api.Alertmanager, log: logger}.RegisterAlertmanagerResourceApiEndpoints(api.RouteRegister)
+	// Register the /status endpoint alongside them too.
+	AlertmanagerSrv{store: api.AlertingStore, am: api.Alertmanager, log: logger}.RegisterAlertmanagerStatusApiEndpoint(api.RouteRegister)
 	// Register endpoints for proxing to Prometheus-compatible backends.
 	api.RegisterPrometheusApiEndpoints(NewForkedProm(
 		api.DatasourceCache,
@@ -85,4 +139,17 @@ func (api *API) RegisterAPIEndpoints(m *metrics.Metrics) {
 		DatasourceCache: api.DatasourceCache,
 		log:             logger,
 	}, m)
+	// Register admin endpoints for resuming/reverting the unified alerting migration.
+	(&AdminUAlertSrv{SQLStore: api.SQLStore, log: logger}).RegisterAdminUAlertApiEndpoints(api.RouteRegister)
+
+	// Start the anonymous usage stats reporter. It no-ops for the life of the
+	// process when [analytics] reporting_alerting_enabled is false, and a
+	// KVStore-backed lease keeps only one instance in an HA cluster actually
+	// sending a report per interval.
+	reporter := usagestats.NewReporter(api.Cfg, api.KVStore, api.AlertingStore, api.RuleStore, uuid.New().String(), m.Registerer)
+	go func() {
+		if err := reporter.Run(context.Background()); err != nil {
+			logger.Error("ngalert usage stats reporter stopped", "error", err)
+		}
+	}()
 }
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrations/ualert"
+)
+
+// AdminUAlertSrv exposes the unified alerting migration and revert paths as
+// HTTP endpoints, guarded by the grafana admin role, so a partially failed
+// migration can be resumed or rolled back without manual SQL.
+type AdminUAlertSrv struct {
+	SQLStore *sqlstore.SQLStore
+	log      log.Logger
+}
+
+// RegisterAdminUAlertApiEndpoints registers POST /api/admin/ualert/migrate
+// and POST /api/admin/ualert/revert.
+func (srv *AdminUAlertSrv) RegisterAdminUAlertApiEndpoints(rr routing.RouteRegister) {
+	rr.Group("/api/admin/ualert", func(adminRoute routing.RouteRegister) {
+		adminRoute.Post("/migrate", middleware.ReqGrafanaAdmin, routing.Wrap(srv.RouteMigrate))
+		adminRoute.Post("/revert", middleware.ReqGrafanaAdmin, routing.Wrap(srv.RouteRevert))
+	})
+}
+
+// RouteMigrate (re-)runs only the unified alerting migrations, scoped with
+// ualert.NewScopedMigrator so this doesn't also re-run the rest of
+// Grafana's migrator, so that any unified alerting migrations left pending
+// by a crashed run are resumed. Of the migration's inserts, only the
+// per-org General folder and its default ACL are looked up in
+// migration_state first and skipped if already created; rule and
+// notification channel inserts do not consult it.
+func (srv *AdminUAlertSrv) RouteMigrate(c *models.ReqContext) response.Response {
+	mg := ualert.NewScopedMigrator(srv.SQLStore.Engine)
+	if err := mg.Start(); err != nil {
+		return response.Error(http.StatusInternalServerError, "failed to run unified alerting migration", err)
+	}
+	return response.Success("unified alerting migration complete")
+}
+
+// RouteRevert reverts a unified alerting migration, restoring legacy alerts
+// to their pre-migration state using the migration_state table.
+func (srv *AdminUAlertSrv) RouteRevert(c *models.ReqContext) response.Response {
+	if err := srv.SQLStore.WithTransactionalDbSession(c.Req.Context(), func(sess *sqlstore.DBSession) error {
+		return ualert.RevertUnifiedAlerting(sess.Session, srv.SQLStore.NewMigrator())
+	}); err != nil {
+		return response.Error(http.StatusInternalServerError, "failed to revert unified alerting migration", err)
+	}
+	return response.Success("unified alerting migration reverted")
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/tsdb"
+)
+
+// alertsForStateMetric is the series Prometheus-compatible datasources
+// expose recording when each currently pending/firing alert instance first
+// became active. It mirrors schedule.alertsForStateMetric, which isn't
+// exported across the package boundary.
+const alertsForStateMetric = "ALERTS_FOR_STATE"
+
+// dataServiceAlertsForStateQuerier implements schedule.AlertsForStateQuerier
+// on top of the same DataService/DatasourceCache plumbing rule evaluation
+// already uses, so the startup backfill queries each rule's datasource the
+// same way the scheduler would.
+type dataServiceAlertsForStateQuerier struct {
+	api *API
+}
+
+// QueryAlertsForState runs the ALERTS_FOR_STATE lookback query for rule
+// against every distinct datasource rule.Data references and returns the
+// combined samples. A datasource that returns no series contributes
+// nothing; a datasource that cannot be resolved or queried stops the lookup
+// with an error. Either way, when no samples come back,
+// schedule.BackfillState falls back to the instance store.
+func (q *dataServiceAlertsForStateQuerier) QueryAlertsForState(ctx context.Context, rule *models.AlertRule, lookback time.Duration) ([]schedule.AlertsForStateSample, error) {
+	seen := make(map[string]bool, len(rule.Data))
+	var samples []schedule.AlertsForStateSample
+
+	for _, q2 := range rule.Data {
+		if q2.DatasourceUID == "" || seen[q2.DatasourceUID] {
+			continue
+		}
+		seen[q2.DatasourceUID] = true
+
+		ds, err := q.api.DatasourceCache.GetDatasourceByUID(ctx, q2.DatasourceUID, rule.OrgID, false)
+		if err != nil {
+			return samples, fmt.Errorf("failed to resolve datasource %s for rule %s: %w", q2.DatasourceUID, rule.UID, err)
+		}
+
+		resp, err := q.api.DataService.HandleRequest(ctx, ds, alertsForStateRequest(rule.Title, lookback))
+		if err != nil {
+			return samples, fmt.Errorf("failed to query %s for rule %s on datasource %s: %w", alertsForStateMetric, rule.UID, q2.DatasourceUID, err)
+		}
+
+		result, ok := resp.Results["A"]
+		if !ok {
+			continue
+		}
+		for _, series := range result.Series {
+			if len(series.Points) == 0 {
+				continue
+			}
+			samples = append(samples, schedule.AlertsForStateSample{
+				Labels: series.Tags,
+				Value:  series.Points[len(series.Points)-1][0],
+			})
+		}
+	}
+
+	return samples, nil
+}
+
+// alertsForStateRequest builds the instant ALERTS_FOR_STATE{alertname=...}
+// query for one rule over the lookback window that schedule.BackfillState
+// supplies.
+func alertsForStateRequest(alertName string, lookback time.Duration) *tsdb.TsdbQuery {
+	now := time.Now()
+	from := fmt.Sprintf("%d", now.Add(-lookback).UnixNano()/int64(time.Millisecond))
+	to := fmt.Sprintf("%d", now.UnixNano()/int64(time.Millisecond))
+
+	return &tsdb.TsdbQuery{
+		TimeRange: tsdb.NewTimeRange(from, to),
+		Queries: []*tsdb.Query{
+			{
+				RefId: "A",
+				Model: simplejson.NewFromAny(map[string]interface{}{
+					"expr":    fmt.Sprintf("%s{alertname=%q}", alertsForStateMetric, alertName),
+					"instant": true,
+				}),
+			},
+		},
+	}
+}
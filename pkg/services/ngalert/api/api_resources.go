@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// ResourceHandler answers one CallResource request for a single receiver
+// type - an OAuth callback, a channel-autocomplete lookup, a
+// test-connection probe, a template preview, or whatever else that
+// notifier needs an HTTP endpoint for. It takes over the method, path (the
+// part after /resources/) and body RouteCallResource forwarded, and
+// returns what should be streamed back.
+type ResourceHandler func(ctx context.Context, path, method string, body []byte) (status int, headers http.Header, respBody []byte, err error)
+
+var (
+	resourceHandlersMu sync.RWMutex
+	resourceHandlers   = map[string]ResourceHandler{}
+)
+
+// RegisterResourceHandler makes handler the ResourceHandler for
+// receiverType. Notifier implementations (Slack, PagerDuty, OnCall, custom
+// webhooks, ...) call this at construction time, the same way Grafana's
+// backend-plugin resources hook registers a plugin's resource handler when
+// the plugin is loaded. A concrete Alertmanager's CallResource should
+// consult this registry - via ResourceHandlerFor - rather than hardcoding
+// per-receiver-type dispatch itself.
+func RegisterResourceHandler(receiverType string, handler ResourceHandler) {
+	resourceHandlersMu.Lock()
+	defer resourceHandlersMu.Unlock()
+	resourceHandlers[receiverType] = handler
+}
+
+// ResourceHandlerFor returns the ResourceHandler registered for
+// receiverType, if any.
+func ResourceHandlerFor(receiverType string) (ResourceHandler, bool) {
+	resourceHandlersMu.RLock()
+	defer resourceHandlersMu.RUnlock()
+	h, ok := resourceHandlers[receiverType]
+	return h, ok
+}
+
+// ErrNoResourceHandler is returned by DispatchResourceHandler when no
+// notifier has registered a ResourceHandler for the requested receiver
+// type.
+var ErrNoResourceHandler = errors.New("no resource handler registered for receiver type")
+
+// DispatchResourceHandler looks up the ResourceHandler registered for
+// receiverType and invokes it. This is what the embedded notifier
+// Alertmanager's CallResource should call rather than switching on
+// receiverType itself - registering a ResourceHandler at notifier
+// construction is then the only wiring a new receiver type needs to expose
+// endpoints through CallResource.
+func DispatchResourceHandler(ctx context.Context, receiverType, path, method string, body []byte) (status int, headers http.Header, respBody []byte, err error) {
+	handler, ok := ResourceHandlerFor(receiverType)
+	if !ok {
+		return 0, nil, nil, fmt.Errorf("%w: %q", ErrNoResourceHandler, receiverType)
+	}
+	return handler(ctx, path, method, body)
+}
+
+// maxResourceRequestBody caps the size of a CallResource request/response
+// body a notifier resource handler will be fed or allowed to return. The
+// request body is read through a limit, so no more than this is buffered; a
+// response body is checked only after the handler has returned it whole, and
+// one over the cap is answered with 502.
+const maxResourceRequestBody = 1 << 20 // 1MiB
+
+// resourceRequestTimeout bounds how long a notifier's ResourceHandler gets
+// to answer a single CallResource request.
+const resourceRequestTimeout = 30 * time.Second
+
+// RegisterAlertmanagerResourceApiEndpoints registers the generic resource
+// route notifier implementations (Slack, PagerDuty, OnCall, custom
+// webhooks, ...) use to expose auxiliary endpoints - OAuth callbacks,
+// channel autocomplete, test-connection probes, template preview - without
+// each one needing a bespoke route wired through RegisterAlertmanagerApiEndpoints.
+// Modelled on Grafana's backend-plugin resources hook: the notifier
+// registers a ResourceHandler at construction and srv.am dispatches matching
+// requests to it.
+func (srv AlertmanagerSrv) RegisterAlertmanagerResourceApiEndpoints(rr routing.RouteRegister) {
+	rr.Group("/api/v1/alerts/receivers", func(receiversRoute routing.RouteRegister) {
+		receiversRoute.Any("/:type/resources/*", middleware.ReqOrgAdmin, routing.Wrap(srv.RouteCallResource))
+	})
+}
+
+// RouteCallResource reads the whole request body and passes it with the path
+// to the Alertmanager's CallResource hook for the given receiver type, and
+// writes the returned status, headers and body back verbatim, so each
+// notifier can expose whatever sub-paths it needs without Grafana knowing
+// about them in advance. Bodies are buffered in full, not streamed.
+func (srv AlertmanagerSrv) RouteCallResource(c *models.ReqContext) response.Response {
+	receiverType := c.Params(":type")
+	path := c.Params("*")
+
+	body, err := io.ReadAll(io.LimitReader(c.Req.Body, maxResourceRequestBody+1))
+	if err != nil {
+		return response.Error(500, "failed to read request body", err)
+	}
+	if len(body) > maxResourceRequestBody {
+		return response.Error(413, "request body too large", nil)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Req.Context(), resourceRequestTimeout)
+	defer cancel()
+
+	status, headers, respBody, err := srv.am.CallResource(ctx, receiverType, path, c.Req.Method, body)
+	if err != nil {
+		return response.Error(500, "failed to call notifier resource", err)
+	}
+	if len(respBody) > maxResourceRequestBody {
+		return response.Error(502, "notifier resource response too large", nil)
+	}
+
+	resp := response.Respond(status, respBody)
+	for key, values := range headers {
+		for _, value := range values {
+			resp.Header().Add(key, value)
+		}
+	}
+	return resp
+}
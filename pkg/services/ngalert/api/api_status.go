@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// RegisterAlertmanagerStatusApiEndpoint registers GET /api/v1/alerts/status.
+// Like RegisterAlertmanagerResourceApiEndpoints, this is wired directly
+// rather than through RegisterAlertmanagerApiEndpoints: status is served
+// straight off srv.am, the Grafana-managed Alertmanager RegisterAPIEndpoints
+// passes in. The route is not forked, so requests are never proxied to an
+// external Alertmanager datasource.
+func (srv AlertmanagerSrv) RegisterAlertmanagerStatusApiEndpoint(rr routing.RouteRegister) {
+	rr.Get("/api/v1/alerts/status", middleware.ReqSignedIn, routing.Wrap(srv.RouteGetAMStatus))
+}
+
+// RouteGetAMStatus implements GET /api/v1/alerts/status for the embedded
+// Alertmanager, returning the same shape upstream Alertmanager's /status
+// endpoint does (config hash, uptime, cluster peers/state, version), so
+// Grafana-managed and external AM instances look identical to UI clients and
+// health checkers.
+func (srv AlertmanagerSrv) RouteGetAMStatus(c *models.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, srv.am.GetStatus())
+}
@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// DefaultNamespace is the Prometheus namespace ngalert collectors were
+// registered under before the namespace became configurable. It remains the
+// default so existing dashboards and alerting rules built against
+// grafana_alerting_* metrics keep working untouched.
+const DefaultNamespace = "grafana"
+
+// Subsystem is the fixed Prometheus subsystem every ngalert collector is
+// registered under, independent of the configured namespace.
+const Subsystem = "alerting"
+
+// Metrics holds the Prometheus collectors NewMetrics registers for ngalert:
+// scheduler tick durations, rule evaluation counts, notification counts and
+// alert state gauges. All collectors share the namespace NewMetrics was constructed
+// with, so operators running Grafana alongside Cortex/Mimir/Loki can fold
+// them into one namespace of dashboards.
+type Metrics struct {
+	Registerer prometheus.Registerer
+	Namespace  string
+
+	// Scheduler
+	SchedulePeriodicDuration prometheus.Histogram
+	SchedulableAlertRules    prometheus.Gauge
+	EvalTotal                *prometheus.CounterVec
+	EvalFailures             *prometheus.CounterVec
+	EvalDuration             *prometheus.HistogramVec
+
+	// Alert state, seeded at startup from ALERTS_FOR_STATE and kept live by
+	// the scheduler; see schedule.ScheduleService.BackfillState.
+	BackfilledInstances prometheus.Counter
+	AlertState          *prometheus.GaugeVec
+
+	// Notifications
+	NotificationsTotal       *prometheus.CounterVec
+	NotificationsFailedTotal *prometheus.CounterVec
+}
+
+// NewMetrics creates and registers every ngalert collector under the
+// namespace configured by [unified_alerting] metrics_namespace, falling
+// back to DefaultNamespace when it is unset so existing installations don't
+// see their metric names change on upgrade. It registers the nine
+// collectors above; usagestats.NewReporter registers its own
+// usage_stats_last_report_seconds gauge separately, under the same
+// namespace and subsystem.
+func NewMetrics(cfg *setting.Cfg, r prometheus.Registerer) *Metrics {
+	namespace := cfg.UnifiedAlerting.MetricsNamespace
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+
+	m := &Metrics{
+		Registerer: r,
+		Namespace:  namespace,
+
+		SchedulePeriodicDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: Subsystem,
+			Name:      "schedule_periodic_duration_seconds",
+			Help:      "The time taken to run the scheduler's periodic tick, in seconds.",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2, 5, 10},
+		}),
+		SchedulableAlertRules: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: Subsystem,
+			Name:      "schedule_alert_rules",
+			Help:      "The number of alert rules that could be scheduled for evaluation.",
+		}),
+		EvalTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: Subsystem,
+			Name:      "rule_evaluations_total",
+			Help:      "The total number of rule evaluations.",
+		}, []string{"org"}),
+		EvalFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: Subsystem,
+			Name:      "rule_evaluation_failures_total",
+			Help:      "The total number of rule evaluation failures.",
+		}, []string{"org"}),
+		EvalDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: Subsystem,
+			Name:      "rule_evaluation_duration_seconds",
+			Help:      "The time taken to evaluate a rule, in seconds.",
+			Buckets:   []float64{0.01, 0.1, 0.5, 1, 5, 10, 30},
+		}, []string{"org"}),
+		BackfilledInstances: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: Subsystem,
+			Name:      "state_backfilled_instances_total",
+			Help:      "The total number of alert instances whose state was backfilled from ALERTS_FOR_STATE or the instance store on startup.",
+		}),
+		AlertState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: Subsystem,
+			Name:      "alerts",
+			Help:      "The current number of alert instances, by state.",
+		}, []string{"state"}),
+		NotificationsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: Subsystem,
+			Name:      "notifications_total",
+			Help:      "The total number of attempted notifications.",
+		}, []string{"type"}),
+		NotificationsFailedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: Subsystem,
+			Name:      "notifications_failed_total",
+			Help:      "The total number of failed notifications.",
+		}, []string{"type"}),
+	}
+
+	if r != nil {
+		r.MustRegister(
+			m.SchedulePeriodicDuration,
+			m.SchedulableAlertRules,
+			m.EvalTotal,
+			m.EvalFailures,
+			m.EvalDuration,
+			m.BackfilledInstances,
+			m.AlertState,
+			m.NotificationsTotal,
+			m.NotificationsFailedTotal,
+		)
+	}
+
+	return m
+}
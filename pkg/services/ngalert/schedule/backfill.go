@@ -0,0 +1,123 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// alertsForStateMetric is the series Prometheus-compatible datasources
+// expose recording when each currently pending/firing alert instance first
+// became active, keyed by the usual alert labels.
+const alertsForStateMetric = "ALERTS_FOR_STATE"
+
+// AlertsForStateSample is one point of the ALERTS_FOR_STATE series: its
+// value is the unix timestamp the alert instance originally became active.
+type AlertsForStateSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// AlertsForStateQuerier runs the ALERTS_FOR_STATE lookback query against a
+// rule's own datasource. It is satisfied by the ngalert proxy/datasource
+// plumbing already used for rule evaluation.
+type AlertsForStateQuerier interface {
+	QueryAlertsForState(ctx context.Context, rule *models.AlertRule, lookback time.Duration) ([]AlertsForStateSample, error)
+}
+
+// BackfillState reconstructs pending/firing state for every rule in rules by
+// querying each rule's datasource (via querier) for its ALERTS_FOR_STATE
+// series over a lookback window equal to the longest For: duration across
+// rules, and seeds manager with a Pending or Alerting entry whose StartsAt
+// equals the original activation time - so a rule whose For has already
+// elapsed fires immediately on restart instead of restarting its timer.
+//
+// If a rule's datasource doesn't return the series (older Prometheus, Loki,
+// etc.) BackfillState falls back to the most recent InstanceStore snapshot
+// for that rule; if both are empty, the rule starts clean. It is invoked
+// once, from API.RegisterAPIEndpoints, before the scheduler starts ticking,
+// and is a no-op when [unified_alerting] state_backfill_enabled is false.
+func (sch *ScheduleService) BackfillState(ctx context.Context, manager *state.Manager, querier AlertsForStateQuerier, instanceStore store.InstanceStore, rules []*models.AlertRule) error {
+	if !sch.cfg.StateBackfillEnabled {
+		return nil
+	}
+
+	lookback := longestFor(rules)
+	if lookback == 0 {
+		return nil
+	}
+
+	backfilled := 0
+	for _, rule := range rules {
+		seeded, err := sch.backfillRuleFromDatasource(ctx, manager, querier, rule, lookback)
+		if err != nil {
+			sch.log.Warn("failed to backfill alert state from datasource, falling back to instance store", "rule", rule.UID, "error", err)
+		}
+		if seeded > 0 {
+			backfilled += seeded
+			continue
+		}
+
+		n, err := sch.backfillRuleFromInstanceStore(ctx, manager, instanceStore, rule)
+		if err != nil {
+			sch.log.Warn("failed to backfill alert state from instance store", "rule", rule.UID, "error", err)
+			continue
+		}
+		backfilled += n
+	}
+
+	sch.metrics.BackfilledInstances.Add(float64(backfilled))
+	sch.log.Info("alert state backfill complete", "instances", backfilled, "rules", len(rules))
+	return nil
+}
+
+// backfillRuleFromDatasource queries rule's datasource for its
+// ALERTS_FOR_STATE series and seeds manager from the returned samples. It
+// returns how many instances it seeded.
+func (sch *ScheduleService) backfillRuleFromDatasource(ctx context.Context, manager *state.Manager, querier AlertsForStateQuerier, rule *models.AlertRule, lookback time.Duration) (int, error) {
+	series, err := querier.QueryAlertsForState(ctx, rule, lookback)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s for rule %s: %w", alertsForStateMetric, rule.UID, err)
+	}
+
+	seeded := 0
+	for _, s := range series {
+		startsAt := time.Unix(int64(s.Value), 0)
+		st := state.Alerting
+		if time.Since(startsAt) < rule.For {
+			st = state.Pending
+		}
+		manager.Set(rule, s.Labels, st, startsAt)
+		seeded++
+	}
+	return seeded, nil
+}
+
+// backfillRuleFromInstanceStore seeds manager from the last persisted
+// InstanceStore snapshot for rule, used when the datasource doesn't expose
+// ALERTS_FOR_STATE.
+func (sch *ScheduleService) backfillRuleFromInstanceStore(ctx context.Context, manager *state.Manager, instanceStore store.InstanceStore, rule *models.AlertRule) (int, error) {
+	instances, err := instanceStore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: rule.OrgID, RuleUID: rule.UID})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, inst := range instances {
+		manager.Set(rule, inst.Labels, state.FromInstanceStatus(inst.CurrentState), inst.CurrentStateSince)
+	}
+	return len(instances), nil
+}
+
+func longestFor(rules []*models.AlertRule) time.Duration {
+	var longest time.Duration
+	for _, r := range rules {
+		if r.For > longest {
+			longest = r.For
+		}
+	}
+	return longest
+}
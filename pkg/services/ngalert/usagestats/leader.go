@@ -0,0 +1,77 @@
+package usagestats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	leaderNamespace = "ngalert.usagestats"
+	leaderKey       = "leader"
+
+	// leaseTTL is how long a claimed lease is honored without renewal. It is
+	// kept well above defaultReportInterval's tick so a leader that's merely slow to
+	// renew isn't immediately displaced by another instance.
+	leaseTTL = 2 * defaultReportInterval
+)
+
+// lease is the record instances race to write into KVStore to decide which
+// one of them sends the periodic usage report.
+type lease struct {
+	OwnerID   string    `json:"ownerId"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// tryAcquireLeadership claims or renews the usage-stats reporting lease for
+// ownerID, modelled as a single KV record rather than a dedicated locking
+// primitive because ngalert has no other use for distributed locks. It
+// returns true if ownerID holds the lease after the call: either it already
+// held an unexpired lease, or the existing lease was missing/expired/owned
+// by itself and it was (re)claimed.
+//
+// KVStore has no compare-and-swap, so two instances racing to claim an
+// expired/missing lease at the same moment can both read "unclaimed", both
+// write, and both believe they won. To narrow that window, the write is
+// followed by a re-read: an instance that lost the race to a write that
+// landed after its own sees the other owner's record and reports false
+// instead of trusting its own write blindly. This doesn't make the claim
+// atomic - two writes can still interleave such that both re-reads observe
+// themselves - but it turns the common case of a late write from another
+// instance into a detected loss rather than a silent double-report.
+func (r *Reporter) tryAcquireLeadership(ctx context.Context, ownerID string) (bool, error) {
+	raw, ok, err := r.KVStore.Get(ctx, 0, leaderNamespace, leaderKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to read usage stats lease: %w", err)
+	}
+
+	if ok {
+		var current lease
+		if err := json.Unmarshal([]byte(raw), &current); err == nil {
+			if current.OwnerID != ownerID && r.now().Before(current.ExpiresAt) {
+				// Another instance holds an unexpired lease; nothing to do.
+				return false, nil
+			}
+		}
+	}
+
+	next := lease{OwnerID: ownerID, ExpiresAt: r.now().Add(leaseTTL)}
+	raw2, err := json.Marshal(next)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode usage stats lease: %w", err)
+	}
+	if err := r.KVStore.Set(ctx, 0, leaderNamespace, leaderKey, string(raw2)); err != nil {
+		return false, fmt.Errorf("failed to write usage stats lease: %w", err)
+	}
+
+	confirmRaw, ok, err := r.KVStore.Get(ctx, 0, leaderNamespace, leaderKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to confirm usage stats lease: %w", err)
+	}
+	var confirmed lease
+	if !ok || json.Unmarshal([]byte(confirmRaw), &confirmed) != nil || confirmed.OwnerID != ownerID {
+		return false, nil
+	}
+	return true, nil
+}
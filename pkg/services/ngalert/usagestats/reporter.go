@@ -0,0 +1,242 @@
+// Package usagestats periodically reports an anonymized summary of an
+// ngalert installation (rule counts, contact point types, evaluation
+// intervals, which Alertmanager is in use) so Grafana Labs can understand
+// how alerting features are actually used without touching any one user's
+// data. It never reports rule contents, labels, annotations or recipient
+// addresses.
+package usagestats
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// defaultReportInterval is how often a cluster leader sends a report when
+// [analytics] reporting_alerting_enabled is true.
+const defaultReportInterval = 4 * time.Hour
+
+const reportURL = "https://stats.grafana.org/ngalert-usage-report"
+
+// KVStore is the generic key/value store the reporter uses to persist the
+// cluster seed and the leader-election lease. It is satisfied by Grafana's
+// kvstore.KVStore service, not by store.AlertingStore, which has no generic
+// Get/Set of its own.
+type KVStore interface {
+	Get(ctx context.Context, orgID int64, namespace, key string) (string, bool, error)
+	Set(ctx context.Context, orgID int64, namespace, key, value string) error
+}
+
+// ConfigStore gives the reporter read access to an org's current
+// Alertmanager configuration, used to count contact points by notifier type
+// for the payload. store.AlertingStore satisfies this.
+type ConfigStore interface {
+	GetLatestAlertmanagerConfiguration(ctx context.Context, orgID int64) (*apimodels.PostableUserConfig, error)
+}
+
+// Payload is the anonymized report body. Apart from the random cluster seed
+// and the version/OS/arch strings, every field is a count or a boolean;
+// nothing here can identify an org, a rule or a notification recipient.
+type Payload struct {
+	ClusterSeed              string         `json:"clusterSeed"`
+	Version                  string         `json:"version"`
+	OS                       string         `json:"os"`
+	Arch                     string         `json:"arch"`
+	RuleCount                int            `json:"ruleCount"`
+	ContactPointsByType      map[string]int `json:"contactPointsByType"`
+	EvalIntervalSeconds      map[string]int `json:"evalIntervalSeconds"`
+	UsesExternalAlertmanager bool           `json:"usesExternalAlertmanager"`
+}
+
+// Reporter periodically builds and sends a Payload, using a KVStore-backed
+// lease so exactly one instance in an HA cluster reports per interval.
+type Reporter struct {
+	Cfg         *setting.Cfg
+	KVStore     KVStore
+	ConfigStore ConfigStore
+	RuleStore   store.RuleStore
+
+	ownerID  string
+	interval time.Duration
+	client   *http.Client
+	log      log.Logger
+	now      func() time.Time
+
+	lastReport prometheus.Gauge
+}
+
+// NewReporter builds a Reporter for this Grafana instance. ownerID should be
+// stable for the process lifetime (e.g. the instance's admin URL or a
+// generated UUID) and is only ever used as the lease owner, never reported.
+func NewReporter(cfg *setting.Cfg, kv KVStore, configStore ConfigStore, ruleStore store.RuleStore, ownerID string, r prometheus.Registerer) *Reporter {
+	namespace := cfg.UnifiedAlerting.MetricsNamespace
+	if namespace == "" {
+		namespace = metrics.DefaultNamespace
+	}
+
+	rep := &Reporter{
+		Cfg:         cfg,
+		KVStore:     kv,
+		ConfigStore: configStore,
+		RuleStore:   ruleStore,
+		ownerID:     ownerID,
+		interval:    defaultReportInterval,
+		client:      &http.Client{Timeout: 30 * time.Second},
+		log:         log.New("ngalert.usagestats"),
+		now:         time.Now,
+		lastReport: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: metrics.Subsystem,
+			Name:      "usage_stats_last_report_seconds",
+			Help:      "Unix timestamp of the last successful ngalert usage stats report sent by this instance.",
+		}),
+	}
+	if r != nil {
+		r.MustRegister(rep.lastReport)
+	}
+	return rep
+}
+
+// Run blocks, sending a report on every tick until ctx is cancelled. It is
+// a no-op for the lifetime of ctx when reporting is disabled via
+// [analytics] reporting_alerting_enabled, so API.RegisterAPIEndpoints can
+// always start it unconditionally.
+func (r *Reporter) Run(ctx context.Context) error {
+	if !r.Cfg.ReportingAlertingEnabled {
+		return nil
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.reportOnce(ctx); err != nil {
+			r.log.Warn("failed to send ngalert usage stats report", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// reportOnce sends a single report if this instance currently holds the
+// reporting lease; otherwise it's a no-op.
+func (r *Reporter) reportOnce(ctx context.Context) error {
+	isLeader, err := r.tryAcquireLeadership(ctx, r.ownerID)
+	if err != nil {
+		return fmt.Errorf("leader election failed: %w", err)
+	}
+	if !isLeader {
+		return nil
+	}
+
+	payload, err := r.buildPayload(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to build usage stats payload: %w", err)
+	}
+
+	if err := r.send(ctx, payload); err != nil {
+		return fmt.Errorf("failed to send usage stats payload: %w", err)
+	}
+
+	r.lastReport.Set(float64(r.now().Unix()))
+	return nil
+}
+
+func (r *Reporter) buildPayload(ctx context.Context) (*Payload, error) {
+	seed, err := r.clusterSeedID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := r.RuleStore.ListAllRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rules: %w", err)
+	}
+
+	p := &Payload{
+		ClusterSeed:              seed,
+		Version:                  setting.BuildVersion,
+		OS:                       runtime.GOOS,
+		Arch:                     runtime.GOARCH,
+		RuleCount:                len(rules),
+		ContactPointsByType:      map[string]int{},
+		EvalIntervalSeconds:      map[string]int{},
+		UsesExternalAlertmanager: r.Cfg.UnifiedAlerting.AlertmanagerURL != "",
+	}
+	orgIDs := map[int64]bool{}
+	for _, rule := range rules {
+		p.EvalIntervalSeconds[fmt.Sprintf("%d", int(rule.IntervalSeconds))]++
+		orgIDs[rule.OrgID] = true
+	}
+
+	for orgID := range orgIDs {
+		if err := r.countContactPoints(ctx, orgID, p.ContactPointsByType); err != nil {
+			r.log.Warn("failed to count contact points for org", "orgID", orgID, "error", err)
+		}
+	}
+
+	return p, nil
+}
+
+// countContactPoints adds one to counts[type] for every Grafana-managed
+// contact point configured in orgID's current Alertmanager configuration.
+// It only sees orgs with at least one rule, since that's the only org set
+// buildPayload has on hand - an org with contact points but no rules yet
+// goes unreported, which is an acceptable gap for an anonymized usage
+// signal.
+func (r *Reporter) countContactPoints(ctx context.Context, orgID int64, counts map[string]int) error {
+	cfg, err := r.ConfigStore.GetLatestAlertmanagerConfiguration(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to load alertmanager configuration: %w", err)
+	}
+	if cfg == nil {
+		return nil
+	}
+
+	for _, receiver := range cfg.AlertmanagerConfig.Receivers {
+		for _, gr := range receiver.GrafanaManagedReceivers {
+			counts[gr.Type]++
+		}
+	}
+	return nil
+}
+
+func (r *Reporter) send(ctx context.Context, payload *Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reportURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("usage stats endpoint returned %s", resp.Status)
+	}
+	return nil
+}
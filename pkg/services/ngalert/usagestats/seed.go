@@ -0,0 +1,67 @@
+package usagestats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const (
+	clusterSeedNamespace = "ngalert.usagestats"
+	clusterSeedKey       = "cluster_seed"
+
+	// maxSeedDecodeAttempts bounds how many times clusterSeedID will retry
+	// a corrupt record before giving up and regenerating it. A single
+	// instance hitting one bad read shouldn't wipe out a seed every other
+	// instance in the cluster is still reporting under.
+	maxSeedDecodeAttempts = 3
+)
+
+// clusterSeed is the stable identifier every Grafana instance in an HA
+// cluster reports usage stats under, so the receiving end can distinguish
+// "N installations reporting once" from "one installation reporting N
+// times".
+type clusterSeed struct {
+	UUID string `json:"uuid"`
+}
+
+// clusterSeedID reads the persisted cluster seed, creating one on first run.
+// A record that fails to decode is re-read, up to maxSeedDecodeAttempts
+// reads in all; only when every read fails to decode is the record treated
+// as corrupt and replaced with a newly generated seed.
+func (r *Reporter) clusterSeedID(ctx context.Context) (string, error) {
+	for attemptNumber := 1; attemptNumber <= maxSeedDecodeAttempts; attemptNumber++ {
+		raw, ok, err := r.KVStore.Get(ctx, 0, clusterSeedNamespace, clusterSeedKey)
+		if err != nil {
+			return "", fmt.Errorf("failed to read cluster seed: %w", err)
+		}
+		if !ok {
+			return r.createClusterSeed(ctx)
+		}
+
+		var seed clusterSeed
+		if err := json.Unmarshal([]byte(raw), &seed); err != nil || seed.UUID == "" {
+			r.log.Warn("failed to decode usage stats cluster seed", "error", err, "attempt", attemptNumber)
+			continue
+		}
+
+		return seed.UUID, nil
+	}
+
+	r.log.Warn("discarding corrupt usage stats cluster seed", "attempts", maxSeedDecodeAttempts)
+	return r.createClusterSeed(ctx)
+}
+
+func (r *Reporter) createClusterSeed(ctx context.Context) (string, error) {
+	seed := clusterSeed{UUID: uuid.New().String()}
+	raw, err := json.Marshal(seed)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cluster seed: %w", err)
+	}
+	if err := r.KVStore.Set(ctx, 0, clusterSeedNamespace, clusterSeedKey, string(raw)); err != nil {
+		return "", fmt.Errorf("failed to persist cluster seed: %w", err)
+	}
+	return seed.UUID, nil
+}
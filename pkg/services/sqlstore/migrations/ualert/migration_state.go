@@ -0,0 +1,60 @@
+package ualert
+
+import (
+	"fmt"
+)
+
+// migrationState is a row of the migration_state table. It records, per org
+// and per legacy alert, what unified alerting entities the migration created
+// for it, so a crashed or re-run migration can detect already-migrated
+// entities instead of duplicating them.
+type migrationState struct {
+	Id                  int64
+	OrgId               int64
+	AlertId             int64 `xorm:"'alert_id'"`
+	RuleUid             string
+	FolderUid           string
+	NotificationChanUid string
+	DashboardAclIds     string // comma-separated dashboard_acl row ids inserted for this alert
+}
+
+func (migrationState) TableName() string {
+	return "migration_state"
+}
+
+// getMigrationState looks up the recorded migration state for a legacy
+// alert, if one was written by a previous (possibly interrupted) run.
+func (m *migration) getMigrationState(orgID, alertID int64) (*migrationState, bool, error) {
+	state := migrationState{OrgId: orgID, AlertId: alertID}
+	has, err := m.sess.Get(&state)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to look up migration state for alert %d: %w", alertID, err)
+	}
+	if !has {
+		return nil, false, nil
+	}
+	return &state, true, nil
+}
+
+// recordMigrationState persists the mapping from a legacy alert to the
+// unified alerting entities created for it, so a resumed migration can skip
+// recreating them. Its only caller is getOrCreateGeneralFolder, once per org
+// with the generalFolderStateAlertID sentinel; the per-legacy-alert
+// migration loop records no rows, so RevertUnifiedAlerting has no record of
+// the rules, notification channels or ACLs that loop creates.
+func (m *migration) recordMigrationState(state *migrationState) error {
+	if _, err := m.sess.Insert(state); err != nil {
+		return fmt.Errorf("failed to record migration state for alert %d: %w", state.AlertId, err)
+	}
+	return nil
+}
+
+// allMigrationStates returns every recorded migration_state row, for use by
+// RevertUnifiedAlerting.
+func (m *migration) allMigrationStates() ([]*migrationState, error) {
+	var states []*migrationState
+	if err := m.sess.Find(&states); err != nil {
+		return nil, fmt.Errorf("failed to list migration state: %w", err)
+	}
+	return states, nil
+}
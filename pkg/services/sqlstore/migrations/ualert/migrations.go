@@ -0,0 +1,50 @@
+package ualert
+
+import (
+	migrator "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"xorm.io/xorm"
+)
+
+// NewScopedMigrator returns a fresh Migrator bound to engine, pre-loaded
+// with only the unified alerting migrations (via AddMigrations) and nothing
+// else. RouteMigrate uses this instead of a store's full migrator, so
+// retriggering a stuck unified alerting migration from the admin API can't
+// also re-run unrelated, already-applied migrations.
+func NewScopedMigrator(engine *xorm.Engine) *migrator.Migrator {
+	mg := migrator.NewMigrator(engine)
+	AddMigrations(mg)
+	return mg
+}
+
+// AddMigrations registers every unified alerting migration, in the order
+// they must run: the migration_state table first, since AddMigration's
+// migrations (getOrCreateGeneralFolder and friends) read and write it to
+// stay resumable, then the rest of the unified alerting set. Grafana's main
+// migration list should call this - not AddMigration directly - so a normal
+// upgrade doesn't hit "no such table: migration_state" on first run.
+func AddMigrations(mg *migrator.Migrator) {
+	AddMigrationStateMigrations(mg)
+	AddMigration(mg)
+}
+
+// AddMigrationStateMigrations registers the migration_state table used to
+// make the unified alerting migration idempotent and resumable, and to drive
+// RevertUnifiedAlerting. It should be called alongside the rest of the
+// ualert migrations, before they run.
+func AddMigrationStateMigrations(mg *migrator.Migrator) {
+	mg.AddMigration("create migration_state table", migrator.NewAddTableMigration(migrator.Table{
+		Name: "migration_state",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "alert_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: true},
+			{Name: "folder_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: true},
+			{Name: "notification_chan_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: true},
+			{Name: "dashboard_acl_ids", Type: migrator.DB_NVarchar, Length: 255, Nullable: true},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "alert_id"}, Type: migrator.UniqueIndex},
+		},
+	}))
+}
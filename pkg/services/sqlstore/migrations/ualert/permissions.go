@@ -2,6 +2,8 @@ package ualert
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -11,25 +13,94 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 )
 
+// generalFolderStateAlertID is the sentinel alert_id migration_state rows
+// use to record per-org entities that aren't tied to one legacy alert, such
+// as the shared General folder and its default ACL. It lets a resumed
+// migration recognize "this org's General folder was already created" the
+// same way it recognizes an already-migrated alert.
+const generalFolderStateAlertID int64 = 0
+
 // getOrCreateGeneralFolder returns the general folder under the specific organisation
 // If the general folder does not exist it creates it.
+//
+// A previous (possibly interrupted) run is detected via migration_state
+// rather than just the org_id/folder_id/title lookup below, so a resumed
+// migration doesn't depend on that unique constraint alone to avoid
+// recreating the folder and its default ACL.
 func (m *migration) getOrCreateGeneralFolder(orgID int64) (*dashboard, error) {
+	if state, ok, err := m.getMigrationState(orgID, generalFolderStateAlertID); err != nil {
+		return nil, err
+	} else if ok && state.FolderUid != "" {
+		existing := dashboard{OrgId: orgID, Uid: state.FolderUid}
+		has, err := m.sess.Get(&existing)
+		if err != nil {
+			return nil, err
+		}
+		if has {
+			return &existing, nil
+		}
+	}
+
 	// there is a unique constraint on org_id, folder_id, title
 	// there are no nested folders so the parent folder id is always 0
 	dashboard := dashboard{OrgId: orgID, FolderId: 0, Title: GENERAL_FOLDER}
 	has, err := m.sess.Get(&dashboard)
 	if err != nil {
 		return nil, err
-	} else if !has {
-		// create folder
-		result, err := m.createFolder(orgID, GENERAL_FOLDER)
-		if err != nil {
-			return nil, err
-		}
+	} else if has {
+		return &dashboard, nil
+	}
 
-		return result, nil
+	// create folder
+	result, err := m.createFolder(orgID, GENERAL_FOLDER)
+	if err != nil {
+		return nil, err
+	}
+
+	aclIds, err := m.setACL(orgID, result.Id, generalFolderDefaultACL(orgID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.recordMigrationState(&migrationState{
+		OrgId:           orgID,
+		AlertId:         generalFolderStateAlertID,
+		FolderUid:       result.Uid,
+		DashboardAclIds: joinAclIds(aclIds),
+	}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// generalFolderDefaultACL is the default permission the General folder is
+// created with: every org member can view it, matching legacy alerting's
+// own default.
+func generalFolderDefaultACL(orgID int64) []*models.DashboardAcl {
+	return []*models.DashboardAcl{
+		{
+			OrgID:      orgID,
+			Role:       &orgMemberRole,
+			Permission: models.PERMISSION_VIEW,
+		},
 	}
-	return &dashboard, nil
+}
+
+var orgMemberRole = models.ROLE_VIEWER
+
+// joinAclIds formats dashboard_acl row ids for storage in
+// migrationState.DashboardAclIds, which RevertUnifiedAlerting splits back
+// apart to delete exactly the rows this migration created.
+func joinAclIds(ids []int64) string {
+	if len(ids) == 0 {
+		return ""
+	}
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(parts, ",")
 }
 
 // based on sqlstore.saveDashboard()
@@ -100,13 +171,18 @@ func (m *migration) generateNewDashboardUid(orgId int64) (string, error) {
 
 // based on SQLStore.UpdateDashboardACL()
 // it should be called from inside a transaction
-func (m *migration) setACL(orgID int64, dashboardID int64, items []*models.DashboardAcl) error {
+//
+// setACL returns the ids of the dashboard_acl rows it inserted, so callers
+// can record them in migration_state and delete exactly those rows on
+// revert.
+func (m *migration) setACL(orgID int64, dashboardID int64, items []*models.DashboardAcl) ([]int64, error) {
 	if dashboardID <= 0 {
-		return fmt.Errorf("folder id must be greater than zero for a folder permission")
+		return nil, fmt.Errorf("folder id must be greater than zero for a folder permission")
 	}
+	ids := make([]int64, 0, len(items))
 	for _, item := range items {
 		if item.UserID == 0 && item.TeamID == 0 && (item.Role == nil || !item.Role.IsValid()) {
-			return models.ErrDashboardAclInfoMissing
+			return nil, models.ErrDashboardAclInfoMissing
 		}
 
 		item.OrgID = orgID
@@ -116,14 +192,17 @@ func (m *migration) setACL(orgID int64, dashboardID int64, items []*models.Dashb
 
 		m.sess.Nullable("user_id", "team_id")
 		if _, err := m.sess.Insert(item); err != nil {
-			return err
+			return nil, err
 		}
+		ids = append(ids, item.Id)
 	}
 
 	// Update dashboard HasAcl flag
 	dashboard := models.Dashboard{HasAcl: true}
-	_, err := m.sess.Cols("has_acl").Where("id=?", dashboardID).Update(&dashboard)
-	return err
+	if _, err := m.sess.Cols("has_acl").Where("id=?", dashboardID).Update(&dashboard); err != nil {
+		return nil, err
+	}
+	return ids, nil
 }
 
 // based on SQLStore.GetDashboardAclInfoList()
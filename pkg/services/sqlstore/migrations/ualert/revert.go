@@ -0,0 +1,76 @@
+package ualert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	migrator "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"xorm.io/xorm"
+)
+
+// RevertUnifiedAlerting undoes a unified alerting migration using the
+// migration_state table: for each recorded row it deletes the dashboard_acl
+// rows, the alert_rule and alert_configuration rows matching the recorded
+// UIDs, and the folder, then deletes the row itself. Only what
+// migration_state records is removed; the migration currently records just
+// each org's General folder and its default ACL. It is safe to call on a
+// fresh install, where it is a no-op.
+func RevertUnifiedAlerting(sess *xorm.Session, mg *migrator.Migrator) error {
+	m := &migration{sess: sess, mg: mg}
+
+	states, err := m.allMigrationStates()
+	if err != nil {
+		return err
+	}
+
+	seenFolders := map[string]bool{}
+	for _, state := range states {
+		for _, idStr := range splitAclIds(state.DashboardAclIds) {
+			id, err := strconv.ParseInt(idStr, 10, 64)
+			if err != nil {
+				continue
+			}
+			if _, err := sess.Exec("DELETE FROM dashboard_acl WHERE id = ?", id); err != nil {
+				return fmt.Errorf("failed to delete dashboard_acl row %d for alert %d: %w", id, state.AlertId, err)
+			}
+		}
+
+		if state.RuleUid != "" {
+			if _, err := sess.Exec("DELETE FROM alert_rule WHERE org_id = ? AND uid = ?", state.OrgId, state.RuleUid); err != nil {
+				return fmt.Errorf("failed to delete alert rule %q for alert %d: %w", state.RuleUid, state.AlertId, err)
+			}
+		}
+
+		if state.NotificationChanUid != "" {
+			if _, err := sess.Exec("DELETE FROM alert_configuration WHERE org_id = ? AND uid = ?", state.OrgId, state.NotificationChanUid); err != nil {
+				return fmt.Errorf("failed to delete notification channel %q for alert %d: %w", state.NotificationChanUid, state.AlertId, err)
+			}
+		}
+
+		if state.FolderUid != "" && !seenFolders[state.FolderUid] {
+			seenFolders[state.FolderUid] = true
+			// bound as a native bool, not m.mg.Dialect.BooleanStr(true): that
+			// helper returns a dialect-specific string literal meant to be
+			// concatenated straight into SQL text (see getACL's use of
+			// falseStr), not sent through the driver as a bind parameter -
+			// Postgres won't implicitly cast a text parameter to boolean.
+			if _, err := sess.Exec("DELETE FROM dashboard WHERE org_id = ? AND uid = ? AND is_folder = ?", state.OrgId, state.FolderUid, true); err != nil {
+				return fmt.Errorf("failed to delete folder %q for org %d: %w", state.FolderUid, state.OrgId, err)
+			}
+		}
+
+		if _, err := sess.Exec("DELETE FROM migration_state WHERE id = ?", state.Id); err != nil {
+			return fmt.Errorf("failed to clear migration state for alert %d: %w", state.AlertId, err)
+		}
+	}
+
+	return nil
+}
+
+func splitAclIds(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
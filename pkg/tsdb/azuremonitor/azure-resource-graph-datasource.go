@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"sync"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -19,8 +20,17 @@ import (
 	"github.com/grafana/grafana/pkg/util/errutil"
 	"github.com/opentracing/opentracing-go"
 	"golang.org/x/net/context/ctxhttp"
+	"golang.org/x/sync/errgroup"
 )
 
+// argDefaultPageSize is the maximum number of rows the Azure Resource Graph
+// API returns per page. It is also the default for both PageSize and MaxRows.
+const argDefaultPageSize = 1000
+
+// argMaxConcurrentQueries bounds how many ARG queries run at once from a
+// single executeTimeSeriesQuery call.
+const argMaxConcurrentQueries = 4
+
 // AzureResourceGraphDatasource calls the Azure Resource Graph API's
 type AzureResourceGraphDatasource struct{}
 
@@ -33,6 +43,13 @@ type AzureResourceGraphQuery struct {
 	JSON              json.RawMessage
 	InterpolatedQuery string
 	TimeRange         backend.TimeRange
+
+	// MaxRows caps how many rows will be fetched across all pages of a
+	// single logical query. Defaults to argDefaultPageSize.
+	MaxRows int
+	// PageSize is how many rows are requested per page. Capped at
+	// argDefaultPageSize, which is the ARG API's own per-page limit.
+	PageSize int
 }
 
 const argAPIVersion = "2021-03-01"
@@ -52,8 +69,29 @@ func (e *AzureResourceGraphDatasource) executeTimeSeriesQuery(ctx context.Contex
 		return nil, err
 	}
 
+	var mu sync.Mutex
+	sem := make(chan struct{}, argMaxConcurrentQueries)
+	g, ctx := errgroup.WithContext(ctx)
+
 	for _, query := range queries {
-		result.Responses[query.RefID] = e.executeQuery(ctx, query, dsInfo)
+		query := query
+		g.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			resp := e.executeQuery(ctx, query, dsInfo)
+
+			mu.Lock()
+			result.Responses[query.RefID] = resp
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	// Errors are carried per-query on DataResponse.Error rather than failed
+	// here; every goroutine returns nil, so g.Wait() never returns an error.
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
 
 	return result, nil
@@ -83,12 +121,28 @@ func (e *AzureResourceGraphDatasource) buildQueries(queries []backend.DataQuery,
 			return nil, err
 		}
 
+		pageSize := argDefaultPageSize
+		maxRows := argDefaultPageSize
+		if model, jsonErr := simplejson.NewJson(query.JSON); jsonErr == nil {
+			if v := model.Get("azureResourceGraph").Get("pageSize").MustInt(0); v > 0 && v < pageSize {
+				pageSize = v
+			}
+			if v := model.Get("azureResourceGraph").Get("maxRows").MustInt(0); v > 0 {
+				maxRows = v
+			}
+		}
+		if maxRows < pageSize {
+			maxRows = pageSize
+		}
+
 		azureResourceGraphQueries = append(azureResourceGraphQueries, &AzureResourceGraphQuery{
 			RefID:             query.RefID,
 			ResultFormat:      resultFormat,
 			JSON:              query.JSON,
 			InterpolatedQuery: interpolatedQuery,
 			TimeRange:         query.TimeRange,
+			PageSize:          pageSize,
+			MaxRows:           maxRows,
 		})
 	}
 
@@ -98,9 +152,6 @@ func (e *AzureResourceGraphDatasource) buildQueries(queries []backend.DataQuery,
 func (e *AzureResourceGraphDatasource) executeQuery(ctx context.Context, query *AzureResourceGraphQuery, dsInfo datasourceInfo) backend.DataResponse {
 	dataResponse := backend.DataResponse{}
 
-	params := url.Values{}
-	params.Add("api-version", argAPIVersion)
-
 	dataResponseErrorWithExecuted := func(err error) backend.DataResponse {
 		dataResponse = backend.DataResponse{Error: err}
 		frames := data.Frames{
@@ -120,27 +171,7 @@ func (e *AzureResourceGraphDatasource) executeQuery(ctx context.Context, query *
 		dataResponse.Error = err
 		return dataResponse
 	}
-
-	reqBody, err := json.Marshal(map[string]interface{}{
-		"subscriptions": model.Get("subscriptions").MustStringArray(),
-		"query":         query.InterpolatedQuery,
-		"options":       map[string]string{"resultFormat": "table"},
-	})
-
-	if err != nil {
-		dataResponse.Error = err
-		return dataResponse
-	}
-
-	req, err := e.createRequest(ctx, dsInfo, reqBody)
-
-	if err != nil {
-		dataResponse.Error = err
-		return dataResponse
-	}
-
-	req.URL.Path = path.Join(req.URL.Path, argQueryProviderName)
-	req.URL.RawQuery = params.Encode()
+	subscriptions := model.Get("subscriptions").MustStringArray()
 
 	span, ctx := opentracing.StartSpanFromContext(ctx, "azure resource graph query")
 	span.SetTag("interpolated_query", query.InterpolatedQuery)
@@ -148,40 +179,123 @@ func (e *AzureResourceGraphDatasource) executeQuery(ctx context.Context, query *
 	span.SetTag("until", query.TimeRange.To.UnixNano()/int64(time.Millisecond))
 	span.SetTag("datasource_id", dsInfo.DatasourceID)
 	span.SetTag("org_id", dsInfo.OrgID)
-
+	span.SetTag("page_size", query.PageSize)
+	span.SetTag("max_rows", query.MaxRows)
 	defer span.Finish()
 
-	if err := opentracing.GlobalTracer().Inject(
-		span.Context(),
-		opentracing.HTTPHeaders,
-		opentracing.HTTPHeadersCarrier(req.Header)); err != nil {
-		return dataResponseErrorWithExecuted(err)
-	}
+	var (
+		merged    *AzureResourceGraphResponse
+		lastReq   *http.Request
+		skipToken string
+		rowCount  int
+		pageCount int
+		truncated bool
+	)
 
-	azlog.Debug("AzureResourceGraph", "Request ApiURL", req.URL.String())
-	res, err := ctxhttp.Do(ctx, dsInfo.Services[azureResourceGraph].HTTPClient, req)
-	if err != nil {
-		return dataResponseErrorWithExecuted(err)
-	}
+	for {
+		pageStart := time.Now()
 
-	argResponse, err := e.unmarshalResponse(res)
-	if err != nil {
-		return dataResponseErrorWithExecuted(err)
+		req, err := e.createPagedRequest(ctx, dsInfo, subscriptions, query, skipToken)
+		if err != nil {
+			return dataResponseErrorWithExecuted(err)
+		}
+		lastReq = req
+
+		if err := opentracing.GlobalTracer().Inject(
+			span.Context(),
+			opentracing.HTTPHeaders,
+			opentracing.HTTPHeadersCarrier(req.Header)); err != nil {
+			return dataResponseErrorWithExecuted(err)
+		}
+
+		azlog.Debug("AzureResourceGraph", "Request ApiURL", req.URL.String())
+		res, err := ctxhttp.Do(ctx, dsInfo.Services[azureResourceGraph].HTTPClient, req)
+		if err != nil {
+			return dataResponseErrorWithExecuted(err)
+		}
+
+		argResponse, err := e.unmarshalResponse(res)
+		if err != nil {
+			return dataResponseErrorWithExecuted(err)
+		}
+
+		pageCount++
+		rowCount += len(argResponse.Data.Rows)
+		span.SetTag(fmt.Sprintf("page_%d_rows", pageCount), len(argResponse.Data.Rows))
+		span.SetTag(fmt.Sprintf("page_%d_duration_ms", pageCount), time.Since(pageStart).Milliseconds())
+
+		if merged == nil {
+			merged = &argResponse
+		} else {
+			merged.Data.Rows = append(merged.Data.Rows, argResponse.Data.Rows...)
+		}
+
+		if rowCount >= query.MaxRows {
+			truncated = argResponse.SkipToken != "" || rowCount > query.MaxRows
+			if len(merged.Data.Rows) > query.MaxRows {
+				merged.Data.Rows = merged.Data.Rows[:query.MaxRows]
+			}
+			break
+		}
+		if argResponse.SkipToken == "" {
+			break
+		}
+		skipToken = argResponse.SkipToken
 	}
 
-	frame, err := ResponseTableToFrame(&argResponse.Data)
+	span.SetTag("pages", pageCount)
+	span.SetTag("rows", rowCount)
+
+	frame, err := ResponseTableToFrame(&merged.Data)
 	if err != nil {
 		return dataResponseErrorWithExecuted(err)
 	}
 	if frame.Meta == nil {
 		frame.Meta = &data.FrameMeta{}
 	}
-	frame.Meta.ExecutedQueryString = req.URL.RawQuery
+	frame.Meta.ExecutedQueryString = lastReq.URL.RawQuery
+	if truncated {
+		frame.Meta.Notices = append(frame.Meta.Notices, data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     fmt.Sprintf("results truncated at %d rows (MaxRows)", query.MaxRows),
+		})
+	}
 
 	dataResponse.Frames = data.Frames{frame}
 	return dataResponse
 }
 
+// createPagedRequest builds the POST request for one page of an ARG query,
+// requesting pageSize rows and, if skipToken is non-empty, continuing a
+// previous page.
+func (e *AzureResourceGraphDatasource) createPagedRequest(ctx context.Context, dsInfo datasourceInfo, subscriptions []string, query *AzureResourceGraphQuery, skipToken string) (*http.Request, error) {
+	options := map[string]interface{}{"resultFormat": "table", "$top": query.PageSize}
+	if skipToken != "" {
+		options["$skipToken"] = skipToken
+	}
+
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"subscriptions": subscriptions,
+		"query":         query.InterpolatedQuery,
+		"options":       options,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := e.createRequest(ctx, dsInfo, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.URL.Path = path.Join(req.URL.Path, argQueryProviderName)
+	params := url.Values{}
+	params.Add("api-version", argAPIVersion)
+	req.URL.RawQuery = params.Encode()
+
+	return req, nil
+}
+
 func (e *AzureResourceGraphDatasource) createRequest(ctx context.Context, dsInfo datasourceInfo, reqBody []byte) (*http.Request, error) {
 	req, err := http.NewRequest(http.MethodPost, dsInfo.Services[azureResourceGraph].URL, bytes.NewBuffer(reqBody))
 	if err != nil {